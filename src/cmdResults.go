@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/store"
+	"perform-cli-framework-go/src/utils"
+	"strings"
+)
+
+// resultsActions results 子命令的操作
+var resultsActions = []command{
+	{"list", "List saved runs, newest last", resultsList},
+	{"show", "Print a saved run with its metadata", resultsShow},
+	{"tag", "Set or remove tags of a saved run", resultsTag},
+	{"export", "Export saved runs as json, csv or a run report", resultsExport},
+	{"trend", "Chart a metric across saved runs", resultsTrend},
+}
+
+// cmdResults 查看和管理结果库中保存的压测：results <action> [flags]
+func cmdResults(args []string) int {
+	if len(args) > 0 {
+		for _, a := range resultsActions {
+			if a.name == args[0] {
+				return a.run(args[1:])
+			}
+		}
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "Usage: %s results <action> [flags]\n\nActions:\n", os.Args[0])
+	for _, a := range resultsActions {
+		_, _ = fmt.Fprintf(os.Stderr, "  %-8s %s\n", a.name, a.desc)
+	}
+	return exitUsage
+}
+
+// queryFlags 结果库的查询参数
+type queryFlags struct {
+	dir      string
+	scenario string
+	worker   string
+	state    string
+	tags     string
+	since    string
+	until    string
+	limit    int
+}
+
+// addQueryFlags 注册结果库目录和查询参数，limit为默认返回的最近的压测个数
+func addQueryFlags(fs *flag.FlagSet, q *queryFlags, limit int) {
+	addStoreFlag(fs, &q.dir)
+	fs.StringVar(&q.scenario, "scenario", "", "Only runs of the scenario `name`")
+	fs.StringVar(&q.worker, "worker", "", "Only runs of the worker `name`")
+	fs.StringVar(&q.state, "state", "", "Only runs in the `state`, e.g. finished, failed")
+	fs.StringVar(&q.tags, "tag", "", "Only runs with all the `tags`, e.g. env=staging,branch=main")
+	fs.StringVar(&q.since, "since", "", "Only runs started after the `time`, e.g. 24h, 7d, 2006-01-02")
+	fs.StringVar(&q.until, "until", "", "Only runs started before the `time`")
+	fs.IntVar(&q.limit, "limit", limit, "Only the latest `n` runs, 0 for all")
+}
+
+func addStoreFlag(fs *flag.FlagSet, dir *string) {
+	fs.StringVar(dir, "store", os.Getenv(store.DirEnv), "Result store `dir`, default $"+store.DirEnv)
+}
+
+func (q *queryFlags) query() (store.Query, error) {
+	res := store.Query{Scenario: q.scenario, Worker: q.worker, State: q.state, Limit: q.limit}
+	var err error
+	if res.Tags, err = utils.ParseLabels(q.tags); err != nil {
+		return res, err
+	}
+	if res.Since, err = store.ParseTime(q.since); err != nil {
+		return res, err
+	}
+	if res.Until, err = store.ParseTime(q.until); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// queryResults 按查询参数读取结果库，出错时返回退出码
+func queryResults(q *queryFlags) ([]*store.Entry, int) {
+	query, err := q.query()
+	if err != nil {
+		logger.Error("Parse query err: %v", err)
+		return nil, exitUsage
+	}
+	s, err := store.Open(q.dir, true)
+	if err != nil {
+		logger.Error("%v", err)
+		return nil, exitError
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	entries, err := s.Query(query)
+	if err != nil {
+		logger.Error("Query result store err: %v", err)
+		return nil, exitError
+	}
+	return entries, exitOK
+}
+
+// resultsList 打印满足条件的压测
+func resultsList(args []string) int {
+	fs := newFlagSet("results list", "[flags]", "List saved runs matching the filters, newest last.")
+	var q queryFlags
+	addQueryFlags(fs, &q, 20)
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	entries, code := queryResults(&q)
+	if code != exitOK {
+		return code
+	}
+	store.PrintList(os.Stdout, entries)
+	return exitOK
+}
+
+// resultsShow 打印一次压测的环境信息和报告
+func resultsShow(args []string) int {
+	fs := newFlagSet("results show", "[flags] run_id", "Print a saved run with its metadata.")
+	var dir string
+	addStoreFlag(fs, &dir)
+	asJSON := fs.Bool("json", false, "Print the saved entry as json")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	s, err := store.Open(dir, true)
+	if err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	e, err := s.Get(fs.Arg(0))
+	if err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+	if *asJSON {
+		js, _ := json.MarshalIndent(e, "", "  ")
+		_, _ = os.Stdout.Write(append(js, '\n'))
+		return exitOK
+	}
+	store.PrintEntry(os.Stdout, e)
+	return exitOK
+}
+
+// resultsTag 修改一次压测的标签，k= 删除标签
+func resultsTag(args []string) int {
+	fs := newFlagSet("results tag", "[flags] run_id key=value [key=...]", "Set tags of a saved run, an empty value removes the tag.")
+	var dir string
+	addStoreFlag(fs, &dir)
+	_ = fs.Parse(args)
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return exitUsage
+	}
+	tags, err := utils.ParseLabels(strings.Join(fs.Args()[1:], ","))
+	if err != nil {
+		logger.Error("Parse tags err: %v", err)
+		return exitUsage
+	}
+	s, err := store.Open(dir, false)
+	if err != nil {
+		logger.Error("%v", err)
+		return exitError
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	err = s.Update(fs.Arg(0), func(e *store.Entry) error {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+		for k, v := range tags {
+			if v == "" {
+				delete(e.Tags, k)
+			} else {
+				e.Tags[k] = v
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Tag run err: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// resultsExport 导出压测，指定ID时只导出这些压测
+func resultsExport(args []string) int {
+	fs := newFlagSet("results export", "[flags] [run_id...]",
+		"Export saved runs matching the filters or the given ids. The report format writes one run as a report json readable by report and compare.")
+	var q queryFlags
+	addQueryFlags(fs, &q, 0)
+	format := fs.String("format", "json", "Export `format`: json, csv or report")
+	metrics := fs.String("metrics", "requests,rps,error_rate,mean,p50,p90,p99,max", "Metric `columns` of the csv format")
+	out := fs.String("o", "", "Write to the `path`, default stdout")
+	_ = fs.Parse(args)
+	var entries []*store.Entry
+	if fs.NArg() > 0 {
+		s, err := store.Open(q.dir, true)
+		if err != nil {
+			logger.Error("%v", err)
+			return exitError
+		}
+		for _, id := range fs.Args() {
+			e, err := s.Get(id)
+			if err != nil {
+				_ = s.Close()
+				logger.Error("%v", err)
+				return exitError
+			}
+			entries = append(entries, e)
+		}
+		_ = s.Close()
+	} else {
+		var code int
+		if entries, code = queryResults(&q); code != exitOK {
+			return code
+		}
+	}
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			logger.Error("Create export file err: %v", err)
+			return exitError
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		w = f
+	}
+	var err error
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries)
+	case "csv":
+		err = store.WriteCSV(w, entries, strings.Split(*metrics, ","))
+	case "report":
+		if len(entries) != 1 {
+			logger.Error("The report format exports exactly one run, got %d", len(entries))
+			return exitUsage
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(entries[0].Report)
+	default:
+		logger.Error("Unknown export format %s, want json, csv or report", *format)
+		return exitUsage
+	}
+	if err != nil {
+		logger.Error("Export err: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// resultsTrend 打印指标在满足条件的压测间的变化
+func resultsTrend(args []string) int {
+	fs := newFlagSet("results trend", "[flags]", "Chart a metric across saved runs matching the filters, oldest first.")
+	var q queryFlags
+	addQueryFlags(fs, &q, 30)
+	metric := fs.String("metric", "p99", "The `metric`: rps, error_rate, mean, max, p99 and other threshold metrics")
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	entries, code := queryResults(&q)
+	if code != exitOK {
+		return code
+	}
+	store.PrintTrend(os.Stdout, entries, *metric)
+	return exitOK
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/dashboard"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/scenario"
+	"perform-cli-framework-go/src/store"
+	"perform-cli-framework-go/src/worker"
+	"syscall"
+)
+
+// cmdRun 本地压测：run [scenario.yaml] [flags]，指定场景文件时命令行中显式指定的参数覆盖场景中的字段
+func cmdRun(args []string) int {
+	cfg = defaultConfig()
+	fs := newFlagSet("run", "[scenario.yaml] [flags]", "Run a local benchmark from flags or a scenario file.")
+	addRunFlags(fs, &cfg)
+	fs.BoolVar(&ui, "ui", false, "Show a live terminal dashboard, falls back to plain logs when stdout is not a terminal")
+	addLogFlags(fs, &logOpts)
+	// 场景文件可以写在参数前面也可以写在后面
+	_ = fs.Parse(args)
+	path := ""
+	if fs.NArg() > 0 {
+		path = fs.Arg(0)
+		_ = fs.Parse(fs.Args()[1:])
+		if fs.NArg() > 0 {
+			logger.Error("Unexpected arguments: %v", fs.Args())
+			return exitUsage
+		}
+	}
+	// 加载场景文件之前配置日志，场景文件的错误也按 -log-format、-log-file 输出
+	if !setupLog() {
+		return exitUsage
+	}
+	var scn *scenario.Scenario
+	if path != "" {
+		var err error
+		scn, err = scenario.LoadFile(path)
+		if err != nil {
+			logger.Error("Load scenario err: %v", err)
+			return exitUsage
+		}
+		flagCfg := cfg
+		cfg, err = scn.Config(defaultConfig())
+		if err != nil {
+			logger.Error("Load scenario err: %v", err)
+			return exitUsage
+		}
+		overrideFlags(fs, &cfg, flagCfg)
+		scn.AddThresholdPercentiles(&cfg)
+	}
+	applyTrace(fs, &cfg)
+	applyRequestLog(fs, &cfg)
+	if err := cfg.Check(); err != nil {
+		logger.Error("Parse args err: %v", err)
+		return exitUsage
+	}
+	return runLocal(scn)
+}
+
+// ui 本地压测时是否显示终端仪表盘
+var ui bool
+
+// watchPause 收到 pauseSignals 时切换暂停/恢复
+func watchPause(r *runner.BenchMarkRunner) {
+	if len(pauseSignals) == 0 {
+		return
+	}
+	pauseSigs := make(chan os.Signal, 1)
+	signal.Notify(pauseSigs, pauseSignals...)
+	go func() {
+		for range pauseSigs {
+			var err error
+			if r.IsPaused() {
+				err = r.Resume()
+			} else {
+				err = r.Pause()
+			}
+			if err != nil {
+				logger.Warning("Toggle pause with err: %v", err)
+			}
+		}
+	}()
+}
+
+// runLocal 在本进程执行一次压测，scn不为空时检查条件并输出结果
+func runLocal(scn *scenario.Scenario) int {
+	benchmarkRunner := runner.NewBenchRunner(cfg.Timeout * 1000 * 1000)
+	signal.Ignore(os.Interrupt, syscall.SIGINT)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGALRM)
+	go func() {
+		pressCount := 0
+		for sig := range sigs {
+			pressCount++
+			if pressCount > 1 {
+				logger.Warning("recev signal %s, force to exit\n", sig)
+				cancel()
+				return
+			}
+			logger.Info("recev signal %s, waiting to exit, press Ctr + c force exit\n", sig)
+			benchmarkRunner.Stop()
+		}
+	}()
+	watchPause(benchmarkRunner)
+	stopUI := func() {}
+	if ui && dashboard.Supported() {
+		uiCtx, uiCancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			dashboard.New(benchmarkRunner, cfg, cancel).Run(uiCtx)
+		}()
+		// 压测结束后先恢复终端，再输出结果
+		stopUI = func() {
+			uiCancel()
+			<-done
+		}
+	} else if ui {
+		logger.Warning("Stdout is not a terminal, use plain log output")
+	}
+	err := benchmarkRunner.Start(ctx, cfg)
+	stopUI()
+	if err != nil {
+		logger.Error("Run benchmark err: %v", err)
+		if errors.Is(err, conf.ErrInvalidConfig) || errors.Is(err, worker.ErrUnknownWorker) {
+			return exitUsage
+		}
+		return exitError
+	}
+	run := benchmarkRunner.CurrentRun()
+	// worker panic 等导致压测以失败结束时 Start 不返回错误
+	failed := run.State != runner.RunFinished
+	if failed {
+		logger.Error("Run %s %s: %s", run.ID, run.State, run.Reason)
+	}
+	if scn == nil {
+		saveResult(&scenario.Report{Run: run, Pass: !failed})
+		if failed {
+			return exitError
+		}
+		return exitOK
+	}
+	rp, err := scn.Finish(run)
+	saveResult(rp)
+	if err != nil {
+		logger.Error("Report scenario err: %v", err)
+		return exitError
+	}
+	if failed {
+		return exitError
+	}
+	if !rp.Pass {
+		logger.Error("Scenario %s failed thresholds", scn.Name)
+		return exitThreshold
+	}
+	return exitOK
+}
+
+// saveResult 保存到 -store 指定的结果库，失败时只记录日志
+func saveResult(rp *scenario.Report) {
+	if storeDir == "" {
+		return
+	}
+	if err := store.Save(storeDir, rp, gitSHA, targetVersion); err != nil {
+		logger.Error("Save result err: %v", err)
+		return
+	}
+	logger.Info("Result saved to %s as %s", storeDir, rp.Run.ID)
+}
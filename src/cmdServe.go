@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/scenario"
+	"perform-cli-framework-go/src/service"
+	"perform-cli-framework-go/src/utils"
+	"perform-cli-framework-go/src/worker"
+	"sync"
+	"syscall"
+)
+
+// cmdServe 以执行器模式启动，压测由控制器通过gRPC下发
+func cmdServe(args []string) int {
+	cfg = defaultConfig()
+	fs := newFlagSet("serve", "-n worker -R controller -L ip -G group -N name [flags]",
+		"Start as an executor serving gRPC and register to the controller.")
+	addRunFlags(fs, &cfg)
+	addServeFlags(fs, &cfg)
+	addLogFlags(fs, &logOpts)
+	_ = fs.Parse(args)
+	if fs.NArg() > 0 {
+		logger.Error("Unexpected arguments: %v", fs.Args())
+		return exitUsage
+	}
+	if !setupLog() {
+		return exitUsage
+	}
+	applyTrace(fs, &cfg)
+	applyRequestLog(fs, &cfg)
+	return serve()
+}
+
+// checkServeConfig 检查执行器服务的配置
+func checkServeConfig() error {
+	if cfg.WorkerName == "" {
+		return fmt.Errorf("you must specify an executor worker name with -n")
+	}
+	if cfg.GrpcCfg.Port <= 0 {
+		return fmt.Errorf("invalid gRPC server port: %d", cfg.GrpcCfg.Port)
+	}
+	if cfg.GrpcCfg.RegistrationCtEndpoint == "" {
+		return fmt.Errorf("you must specify a remote controller endpoint with -R when using gRPC")
+	}
+	if cfg.GrpcCfg.LocalIp == "" {
+		return fmt.Errorf("you must specify a local IP address with -L when using gRPC")
+	}
+	if cfg.GrpcCfg.GroupName == "" {
+		return fmt.Errorf("you must specify a group name with -G when using gRPC")
+	}
+	if cfg.GrpcCfg.Name == "" {
+		return fmt.Errorf("you must specify an executor name with -N when using gRPC")
+	}
+	if cfg.GrpcCfg.HeartbeatInterval <= 0 {
+		return fmt.Errorf("invalid heartbeat interval: %d", cfg.GrpcCfg.HeartbeatInterval)
+	}
+	if cfg.GrpcCfg.SafetyStop < 0 {
+		return fmt.Errorf("invalid safety stop timeout: %d", cfg.GrpcCfg.SafetyStop)
+	}
+	return cfg.Check()
+}
+
+// serve 注册到控制器并启动gRPC服务，阻塞直到服务停止
+func serve() int {
+	cfg.GrpcCfg.Enable = true
+	if err := checkServeConfig(); err != nil {
+		logger.Error("Parse args err: %v", err)
+		return exitUsage
+	}
+	w, err := worker.NewWorker(cfg.WorkerName)
+	if err != nil {
+		logger.Error("Create worker err: %v", err)
+		return exitUsage
+	}
+	if cfg.WorkerConfig == "{}" {
+		cfg.WorkerConfig = w.DefaultConfig()
+	}
+	lb, err := utils.ParseLabels(labels)
+	if err != nil {
+		logger.Error("Parse labels err: %v", err)
+		return exitUsage
+	}
+	logger.SetField("executor", cfg.GrpcCfg.Name)
+	logger.SetField("group", cfg.GrpcCfg.GroupName)
+	reg := utils.NewRegistrationUtils()
+	benchmarkRunner := runner.NewBenchRunner(cfg.Timeout * 1000 * 1000)
+	// 控制器下发的压测配置中没有trace、requestLog、hlog、sinks时使用执行器的 -trace、-request-log、-hlog、-sink 参数
+	benchmarkRunner.SetTrace(cfg.Trace)
+	benchmarkRunner.SetRequestLog(cfg.RequestLog)
+	benchmarkRunner.SetHlog(cfg.Hlog)
+	benchmarkRunner.SetSinks(cfg.Sinks)
+	// saving 退出前等待仍在保存的结果，closed 之后结束的压测不再保存，避免 Add 与 Wait 并发
+	var (
+		saving sync.WaitGroup
+		saveM  sync.Mutex
+		closed bool
+	)
+	defer func() {
+		saveM.Lock()
+		closed = true
+		saveM.Unlock()
+		saving.Wait()
+	}()
+	if storeDir != "" {
+		// 执行器保存本地的压测结果，场景条件由控制器检查，失败的压测记为不通过
+		// 回调在压测协程中，结果库可能被其他进程锁住，在单独的协程中保存，不阻塞压测的结束
+		benchmarkRunner.SetFinishHook(func(run runner.RunInfo) {
+			saveM.Lock()
+			defer saveM.Unlock()
+			if closed {
+				logger.Warning("Executor is exiting, skip saving run %s", run.ID)
+				return
+			}
+			saving.Add(1)
+			go func() {
+				defer saving.Done()
+				saveResult(&scenario.Report{Scenario: run.Config.Tags["scenario"], Run: &run, Pass: run.State == runner.RunFinished})
+			}()
+		})
+	}
+	utils.SetInstance(cfg.GrpcCfg.Name)
+	signal.Ignore(os.Interrupt, syscall.SIGINT)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var adminSrv *http.Server
+	if adminAddr != "" {
+		adminSrv = service.NewAdminServer(adminAddr, benchmarkRunner, cfg)
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGALRM)
+	go func() {
+		pressCount := 0
+		for sig := range sigs {
+			pressCount++
+			if pressCount > 1 {
+				logger.Warning("recev signal %s, force to exit\n", sig)
+				cancel()
+				return
+			}
+			logger.Info("recev signal %s, waiting to exit, press Ctr + c force exit\n", sig)
+			benchmarkRunner.Stop()
+			err := reg.Unregister()
+			if err != nil {
+				logger.Error("Unregister with err: %v\n", err)
+			}
+			service.StopGrpcServer()
+			if adminSrv != nil {
+				_ = adminSrv.Close()
+			}
+		}
+	}()
+	watchPause(benchmarkRunner)
+	caps := utils.NewCapabilities(maxRate, lb, worker.WorkerInfos())
+	if adminAddr != "" {
+		caps.Admin = advertiseAddr(adminAddr, cfg.GrpcCfg.LocalIp)
+	}
+	reg.SetCapabilities(caps)
+	err = reg.Register(cfg)
+	if err != nil {
+		logger.Error("Can not connect to remote ctl %s with err: %v", cfg.GrpcCfg.RegistrationCtEndpoint, err)
+		return exitError
+	}
+	go reg.Heartbeat(ctx, func() utils.HeartbeatStatus {
+		st := utils.HeartbeatStatus{Running: benchmarkRunner.IsRunning()}
+		if run := benchmarkRunner.CurrentRun(); run != nil {
+			st.RunID, st.RunState = run.ID, run.State.String()
+		}
+		return st
+	}, func() {
+		logger.Warning("Lost remote controller for %d s, safety stop", cfg.GrpcCfg.SafetyStop)
+		benchmarkRunner.Stop()
+	})
+	if adminSrv != nil {
+		go func() {
+			if err := service.StartAdminServer(adminSrv); err != nil {
+				logger.Error("Start admin server failed with err: %v", err)
+			}
+		}()
+	}
+	err = service.StartGrpcServer(cfg.GrpcCfg.Port, benchmarkRunner)
+	if err != nil {
+		logger.Error("Start grpc failed with err: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// advertiseAddr 上报给控制器的地址，监听地址没有写主机或为 0.0.0.0 时与gRPC地址一样使用 -L 指定的本机ip
+func advertiseAddr(addr, localIp string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = localIp
+	}
+	return net.JoinHostPort(host, port)
+}
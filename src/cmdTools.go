@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+	"perform-cli-framework-go/src/controller"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/scenario"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/worker"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// cmdWorkers 打印支持的工作器
+func cmdWorkers(args []string) int {
+	fs := newFlagSet("workers", "", "List supported workers with their default configs.")
+	_ = fs.Parse(args)
+	return printWorkers()
+}
+
+func printWorkers() int {
+	all := worker.GetAllWorkers()
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, wN := range names {
+		// 获取默认配置的 JSON 字符串
+		jsonConfig := all[wN].DefaultConfig()
+
+		// 将 JSON 字符串解码为一个通用接口
+		var jsonData interface{}
+		err := json.Unmarshal([]byte(jsonConfig), &jsonData)
+		if err != nil {
+			logger.Error("Error unmarshalling JSON: %v", err)
+			continue
+		}
+
+		// 使用 MarshalIndent 格式化 JSON
+		prettyJSONBytes, err := json.MarshalIndent(jsonData, "", "    ") // 4个空格的缩进
+		if err != nil {
+			logger.Error("Error formatting JSON: %v", err)
+			continue
+		}
+
+		// 记录工作名称和格式化后的 JSON
+		logger.Info("Worker: %s, with default config = %s", wN, string(prettyJSONBytes))
+	}
+	return exitOK
+}
+
+// cmdReport 打印 run 的 json 输出保存的结果
+func cmdReport(args []string) int {
+	fs := newFlagSet("report", "result.json", "Print a run report saved by a scenario json output.")
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitUsage
+	}
+	rp, err := scenario.ReadReport(fs.Arg(0))
+	if err != nil {
+		logger.Error("Read report err: %v", err)
+		return exitError
+	}
+	rp.Print(os.Stdout)
+	return exitOK
+}
+
+// cmdCompare 对比两次压测的结果，有指标回退时以 exitRegression 退出
+func cmdCompare(args []string) int {
+	fs := newFlagSet("compare", "[flags] base current", "Compare throughput, error rate and latency of two runs and detect regressions. "+
+		"Inputs are run reports (json) or interval histogram logs (.hlog).")
+	tolerance := fs.String("tolerance", "5%", "Relative `tolerance` of a metric, e.g. 5% or 0.05, error_rate uses an absolute 0.1% by default")
+	metricTol := fs.String("metric-tolerance", "", "Per metric `tolerances`, e.g. p99=10%,error_rate=0.5%")
+	alpha := fs.Float64("alpha", scenario.DefaultAlpha, "Significance `level` of the Mann-Whitney U test over interval samples")
+	metrics := fs.String("metrics", "", "Comma separated `metrics` that can fail the comparison, e.g. rps,p99,error_rate, default all")
+	warmup := fs.Duration("warmup", 0, "Skip intervals in the first `duration` of each run in the significance test")
+	percentiles := scenario.CompareSpectrum
+	fs.Var(percentilesFlag{&percentiles}, "percentiles", "Compared `percentiles` of .hlog inputs, default 50,75,90,95,99,99.9,99.99")
+	asJSON := fs.Bool("json", false, "Print the comparison as json")
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 || *alpha <= 0 || *alpha >= 1 || *warmup < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	opts := scenario.CompareOptions{Alpha: *alpha, Warmup: warmup.Microseconds()}
+	var err error
+	if opts.Tolerance, err = scenario.ParseTolerance(*tolerance); err != nil {
+		logger.Error("%v", err)
+		return exitUsage
+	}
+	if opts.Tolerances, err = scenario.ParseTolerances(*metricTol); err != nil {
+		logger.Error("%v", err)
+		return exitUsage
+	}
+	for _, m := range strings.Split(*metrics, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			opts.Metrics = append(opts.Metrics, m)
+		}
+	}
+	base, err := scenario.LoadResult(fs.Arg(0), percentiles)
+	if err != nil {
+		logger.Error("Read base err: %v", err)
+		return exitError
+	}
+	current, err := scenario.LoadResult(fs.Arg(1), percentiles)
+	if err != nil {
+		logger.Error("Read current err: %v", err)
+		return exitError
+	}
+	cmp := scenario.Compare(base, current, opts)
+	if *asJSON {
+		js, _ := json.MarshalIndent(cmp, "", "  ")
+		_, _ = os.Stdout.Write(append(js, '\n'))
+	} else {
+		scenario.PrintCompare(os.Stdout, cmp)
+	}
+	if !cmp.Pass {
+		return exitRegression
+	}
+	return exitOK
+}
+
+// cmdReplay 把请求日志回放为压测报告
+func cmdReplay(args []string) int {
+	fs := newFlagSet("replay", "[flags] requests.jsonl.gz [more logs...]", "Replay raw request logs written by -request-log into a run report, logs of several executors are merged.")
+	var percentiles []float64
+	fs.Var(percentilesFlag{&percentiles}, "percentiles", "Reported `percentiles`, e.g. 50,90,99,p99.9,max, default 50,90,95,99")
+	window := fs.Duration("window", 0, "Print a timeline of the `duration` windows, e.g. 1s, 100ms, 0 to disable")
+	out := fs.String("o", "", "Write the report json to the `path`, readable by report and compare")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 || *window < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	rs, err := scenario.Replay(fs.Args(), percentiles, window.Microseconds())
+	if err != nil {
+		logger.Error("Replay request log err: %v", err)
+		return exitError
+	}
+	rs.Print(os.Stdout)
+	if *out != "" {
+		js, err := json.MarshalIndent(rs.Report, "", "  ")
+		if err == nil {
+			err = os.WriteFile(*out, js, 0644)
+		}
+		if err != nil {
+			logger.Error("Write report err: %v", err)
+			return exitError
+		}
+		logger.Info("Result written to %s", *out)
+	}
+	return exitOK
+}
+
+// cmdMergeHlog 合并多个执行器的区间直方图日志
+func cmdMergeHlog(args []string) int {
+	fs := newFlagSet("merge-hlog", "[flags] e1.hlog e2.hlog [more logs...]", "Merge HdrHistogram interval logs written by -hlog, e.g. of several executors, into one log.")
+	out := fs.String("o", "", "Merged log `path`, default stdout")
+	sum := fs.Duration("sum", 0, "Sum histograms of all logs into one untagged histogram per `interval`, e.g. 1s, 0 to keep the tag of each log")
+	_ = fs.Parse(args)
+	if fs.NArg() == 0 || *sum < 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	hs, err := stat.MergeHlogs(fs.Args(), sum.Milliseconds())
+	if err != nil {
+		logger.Error("Read hlog err: %v", err)
+		return exitError
+	}
+	if len(hs) == 0 {
+		logger.Error("No interval histograms in %v", fs.Args())
+		return exitError
+	}
+	w := os.Stdout
+	if *out != "" {
+		if w, err = os.Create(*out); err != nil {
+			logger.Error("Create hlog err: %v", err)
+			return exitError
+		}
+		defer func() {
+			_ = w.Close()
+		}()
+	}
+	hw, err := stat.NewHlogWriter(w, hs[0].StartTimeMs(), "", "merged from "+strings.Join(fs.Args(), " "))
+	for i := 0; err == nil && i < len(hs); i++ {
+		err = hw.Write(hs[i])
+	}
+	if err != nil {
+		logger.Error("Write hlog err: %v", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// cmdController 以控制器模式启动，管理注册上来的执行器
+func cmdController(args []string) int {
+	fs := newFlagSet("controller", "[flags]", "Start the controller managing executor groups over http.")
+	listen := fs.String("l", ":8080", "Controller http listen address")
+	addLogFlags(fs, &logOpts)
+	_ = fs.Parse(args)
+	if !setupLog() {
+		return exitUsage
+	}
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sigs
+		logger.Info("recev signal %s, stop controller", sig)
+		controller.StopHttpServer()
+	}()
+	err := controller.StartHttpServer(*listen, controller.New())
+	if err != nil {
+		logger.Error("Start controller failed with err: %v", err)
+		return exitError
+	}
+	return exitOK
+}
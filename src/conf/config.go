@@ -2,7 +2,12 @@ package conf
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
 	"perform-cli-framework-go/src/stat"
+	"time"
 
 	"go.uber.org/ratelimit"
 )
@@ -14,6 +19,34 @@ type GrpcConf struct {
 	RegistrationCtEndpoint string
 	LocalIp                string
 	GroupName              string
+	// HeartbeatInterval 向控制器发送心跳的间隔(s)
+	HeartbeatInterval int64
+	// SafetyStop 压测过程中与控制器失联超过该秒数时自动停止压测，0表示不停止
+	SafetyStop int64
+}
+
+// TraceConf 压测请求的链路追踪配置，Endpoint为空时不开启
+type TraceConf struct {
+	// Endpoint http(s):// 开头时为 OTLP/HTTP collector 地址，否则为写入 OTLP JSON 的文件路径
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// Sample 采样比例(0, 1]，0表示使用默认的1%
+	Sample float64 `json:"sample,omitempty" yaml:"sample"`
+}
+
+// RequestLogConf 逐请求的原始样本日志配置，Path为空时不开启
+type RequestLogConf struct {
+	// Path 日志文件路径，.bin 或 .bin.gz 为二进制格式，其他为JSONL，.gz 结尾时gzip压缩，{run_id} 替换为压测ID
+	Path string `json:"path" yaml:"path"`
+	// Sample 采样比例(0, 1]，0表示记录全部请求
+	Sample float64 `json:"sample,omitempty" yaml:"sample"`
+	// Buffer 等待写入的请求数上限，写入跟不上时丢弃新的请求，0表示使用默认的65536
+	Buffer int `json:"buffer,omitempty" yaml:"buffer"`
+}
+
+// Stage 压测的一个阶段，在Duration秒内以Rate的速率发压，Rate为0时不限速
+type Stage struct {
+	Duration int64 `json:"duration"`
+	Rate     int64 `json:"rate"`
 }
 
 type BenchConfig struct {
@@ -25,8 +58,140 @@ type BenchConfig struct {
 	PError       bool   `json:"pError"`
 	WorkerName   string `json:"workerName"`
 	WorkerConfig string `json:"workerConfig"`
-	ListWorker   bool
-	GrpcCfg      GrpcConf `json:"-"`
+	// DrainTimeout 停止时等待执行中请求完成的宽限期(s)，0表示使用默认的5s
+	DrainTimeout int64 `json:"drainTimeout"`
+	StatInterval int64 `json:"statInterval"`
+	// StartAt 开始发压的时间点，unix时间戳(us)，为0时全局前置完成后立即开始
+	StartAt int64 `json:"startAt"`
+	// Stages 分阶段发压，设置后忽略Duration和Rate
+	Stages []Stage `json:"stages"`
+	// Feeders 压测数据源，工作器通过 GoData.Feeders 按名称读取
+	Feeders []FeederConf `json:"feeders,omitempty"`
+	// ReportInterval 控制台打印区间统计的周期(s)，0表示使用默认的30s
+	ReportInterval int64 `json:"reportInterval,omitempty"`
+	// Percentiles 控制台、报告和gRPC返回中输出的分位数，100表示最大值，为空时使用 stat.SummaryPercentiles
+	Percentiles []float64 `json:"percentiles,omitempty"`
+	// Trace 压测请求的链路追踪，为nil时使用执行器的 -trace 参数
+	Trace *TraceConf `json:"trace,omitempty"`
+	// Hlog 每个统计区间的时延直方图写入的 HdrHistogram 日志(.hlog)路径，{run_id} 替换为压测ID，为空时使用执行器的 -hlog 参数
+	Hlog string `json:"hlog,omitempty"`
+	// RequestLog 逐请求的原始样本日志，为nil时使用执行器的 -request-log 参数
+	RequestLog *RequestLogConf `json:"requestLog,omitempty"`
+	// Sinks 区间统计和最终汇总推送的目标，例如 statsd://127.0.0.1:8125、graphite://127.0.0.1:2003，为空时使用执行器的 -sink 参数
+	Sinks []string `json:"sinks,omitempty"`
+	// Tags 压测的标签，随压测记录保存
+	Tags       map[string]string `json:"tags,omitempty"`
+	ListWorker bool
+	GrpcCfg    GrpcConf `json:"-"`
+}
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Check 检查压测配置的有效性
+func (c *BenchConfig) Check() error {
+	if c.WorkerName == "" {
+		return fmt.Errorf("%w: worker name is empty", ErrInvalidConfig)
+	}
+	if c.Workers <= 0 {
+		return fmt.Errorf("%w: workers must be positive, got %d", ErrInvalidConfig, c.Workers)
+	}
+	if c.Nums < 0 {
+		return fmt.Errorf("%w: nums must not be negative, got %d", ErrInvalidConfig, c.Nums)
+	}
+	for i, st := range c.Stages {
+		if st.Duration <= 0 || st.Rate < 0 {
+			return fmt.Errorf("%w: stage %d must have positive duration and non-negative rate", ErrInvalidConfig, i)
+		}
+	}
+	if c.Nums == 0 && c.Duration <= 0 && len(c.Stages) == 0 {
+		return fmt.Errorf("%w: duration must be positive when nums is 0, got %d", ErrInvalidConfig, c.Duration)
+	}
+	if c.Rate < 0 {
+		return fmt.Errorf("%w: rate must not be negative, got %d", ErrInvalidConfig, c.Rate)
+	}
+	if c.DrainTimeout < 0 || c.StatInterval < 0 {
+		return fmt.Errorf("%w: drainTimeout and statInterval must not be negative", ErrInvalidConfig)
+	}
+	if c.ReportInterval < 0 {
+		return fmt.Errorf("%w: reportInterval must not be negative", ErrInvalidConfig)
+	}
+	for _, p := range c.Percentiles {
+		if p <= 0 || p > 100 {
+			return fmt.Errorf("%w: percentile must be in (0, 100], got %v", ErrInvalidConfig, p)
+		}
+	}
+	names := make(map[string]bool, len(c.Feeders))
+	for i := range c.Feeders {
+		if err := c.Feeders[i].check(); err != nil {
+			return err
+		}
+		if names[c.Feeders[i].Name] {
+			return fmt.Errorf("%w: duplicate feeder %s", ErrInvalidConfig, c.Feeders[i].Name)
+		}
+		names[c.Feeders[i].Name] = true
+	}
+	if c.Trace != nil && (c.Trace.Sample < 0 || c.Trace.Sample > 1) {
+		return fmt.Errorf("%w: trace sample must be in [0, 1], got %v", ErrInvalidConfig, c.Trace.Sample)
+	}
+	if rl := c.RequestLog; rl != nil && (rl.Sample < 0 || rl.Sample > 1 || rl.Buffer < 0) {
+		return fmt.Errorf("%w: request log sample must be in [0, 1] and buffer must not be negative", ErrInvalidConfig)
+	}
+	for _, spec := range c.Sinks {
+		if u, err := url.Parse(spec); err != nil || u.Scheme == "" {
+			return fmt.Errorf("%w: invalid sink %q, want e.g. statsd://127.0.0.1:8125", ErrInvalidConfig, spec)
+		}
+	}
+	if c.WorkerConfig != "" && !json.Valid([]byte(c.WorkerConfig)) {
+		return fmt.Errorf("%w: workerConfig is not valid json", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// Schedule 返回发压的各个阶段，没有设置Stages时为Duration和Rate组成的单个阶段
+func (c *BenchConfig) Schedule() []Stage {
+	if len(c.Stages) > 0 {
+		return c.Stages
+	}
+	return []Stage{{Duration: c.Duration, Rate: c.Rate}}
+}
+
+// TotalDuration 返回各个阶段的总时长(s)
+func (c *BenchConfig) TotalDuration() int64 {
+	total := int64(0)
+	for _, st := range c.Schedule() {
+		total += st.Duration
+	}
+	return total
+}
+
+// defaultReportInterval 默认的控制台打印周期(s)
+const defaultReportInterval = 30
+
+// ReportEvery 控制台打印区间统计的周期
+func (c *BenchConfig) ReportEvery() time.Duration {
+	if c.ReportInterval > 0 {
+		return time.Duration(c.ReportInterval) * time.Second
+	}
+	return defaultReportInterval * time.Second
+}
+
+// defaultDrainTimeout 默认的排空宽限期(s)，gRPC下发的配置未设置时也使用该值
+const defaultDrainTimeout = 5
+
+// Drain 停止时等待执行中请求完成的宽限期
+func (c *BenchConfig) Drain() time.Duration {
+	if c.DrainTimeout > 0 {
+		return time.Duration(c.DrainTimeout) * time.Second
+	}
+	return defaultDrainTimeout * time.Second
+}
+
+// ReportPercentiles 需要输出的分位数
+func (c *BenchConfig) ReportPercentiles() []float64 {
+	if len(c.Percentiles) > 0 {
+		return c.Percentiles
+	}
+	return stat.SummaryPercentiles
 }
 
 type GoData struct {
@@ -35,4 +200,33 @@ type GoData struct {
 	Ctx         context.Context
 	StaterI     stat.Stater
 	SendTotal   int64
+	// Feeders 按名称索引的数据源，所有协程共享
+	Feeders map[string]*Feeder
+	// RunID 本次压测的ID
+	RunID string
+	// Goroutine 发压协程的序号，从0开始
+	Goroutine int64
+	// Operation 工作器在DoWorker中设置的本次请求的操作名，例如 "GET /login"，每次请求前清空
+	// 用于链路追踪的span名称和请求日志，为空时使用工作器名称
+	Operation string
+}
+
+// Count 记录自定义计数指标，例如缓存命中数，随区间统计、汇总、报告、gRPC 统计和指标推送输出
+func (d *GoData) Count(name string, delta int64) {
+	d.StaterI.Count(name, delta)
+}
+
+// Gauge 记录自定义仪表指标的当前值，例如队列长度
+func (d *GoData) Gauge(name string, value float64) {
+	d.StaterI.Gauge(name, value)
+}
+
+// Observe 在自定义直方图指标中记录一个值，例如每批的条数，值的范围为 0 到 stat.MetricHighest
+func (d *GoData) Observe(name string, value int64) {
+	d.StaterI.Observe(name, value)
+}
+
+// Phase 记录本次请求在某个阶段的耗时(us)，阶段名称见 stat.PhaseDNS 等，也可以是工作器自定义的阶段
+func (d *GoData) Phase(phase string, us int64) {
+	d.StaterI.RecordPhase(phase, us)
 }
@@ -0,0 +1,125 @@
+package conf
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrFeederExhausted 不循环的数据源已经读完
+var ErrFeederExhausted = errors.New("feeder exhausted")
+
+// FeederConf 压测数据源，为工作器按顺序提供数据
+type FeederConf struct {
+	Name string `json:"name" yaml:"name"`
+	File string `json:"file" yaml:"file"`
+	// Format csv(第一行为表头) 或 lines(每行一条，字段名为line)，默认按文件后缀判断
+	Format string `json:"format,omitempty" yaml:"format"`
+	// Loop 读完后从头开始，否则返回 ErrFeederExhausted
+	Loop bool `json:"loop,omitempty" yaml:"loop"`
+}
+
+// Feeder 加载到内存中的数据源，可以被多个协程并发读取
+type Feeder struct {
+	Name    string
+	records []map[string]string
+	loop    bool
+	next    atomic.Int64
+}
+
+// Next 返回下一条数据
+func (f *Feeder) Next() (map[string]string, error) {
+	i := f.next.Add(1) - 1
+	if i >= int64(len(f.records)) {
+		if !f.loop || len(f.records) == 0 {
+			return nil, ErrFeederExhausted
+		}
+		i %= int64(len(f.records))
+	}
+	return f.records[i], nil
+}
+
+// Len 数据条数
+func (f *Feeder) Len() int {
+	return len(f.records)
+}
+
+func (fc *FeederConf) format() string {
+	if fc.Format != "" {
+		return fc.Format
+	}
+	if strings.HasSuffix(fc.File, ".csv") {
+		return "csv"
+	}
+	return "lines"
+}
+
+// check 检查数据源配置
+func (fc *FeederConf) check() error {
+	if fc.Name == "" || fc.File == "" {
+		return fmt.Errorf("%w: feeder name and file are required", ErrInvalidConfig)
+	}
+	if f := fc.format(); f != "csv" && f != "lines" {
+		return fmt.Errorf("%w: unknown feeder format %s", ErrInvalidConfig, f)
+	}
+	return nil
+}
+
+// OpenFeeders 加载所有数据源
+func OpenFeeders(fcs []FeederConf) (map[string]*Feeder, error) {
+	res := make(map[string]*Feeder, len(fcs))
+	for _, fc := range fcs {
+		f, err := openFeeder(fc)
+		if err != nil {
+			return nil, fmt.Errorf("open feeder %s: %w", fc.Name, err)
+		}
+		res[fc.Name] = f
+	}
+	return res, nil
+}
+
+func openFeeder(fc FeederConf) (*Feeder, error) {
+	file, err := os.Open(fc.File)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	f := &Feeder{Name: fc.Name, loop: fc.Loop}
+	if fc.format() == "lines" {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			if line := scanner.Text(); line != "" {
+				f.records = append(f.records, map[string]string{"line": line})
+			}
+		}
+		return f, scanner.Err()
+	}
+	r := csv.NewReader(file)
+	header, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return f, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		record := make(map[string]string, len(header))
+		for i, k := range header {
+			if i < len(row) {
+				record[k] = row[i]
+			}
+		}
+		f.records = append(f.records, record)
+	}
+}
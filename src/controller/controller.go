@@ -0,0 +1,553 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/perform_pb"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+// rpcTimeout 调用执行器rpc的超时时间
+const rpcTimeout = 10 * time.Second
+
+// startTimeout StartPerform 的超时时间，执行器返回前要完成工作器的全局前置，耗时可能远超 rpcTimeout
+const startTimeout = 5 * time.Minute
+
+// staleBeats 执行器超过该数量的心跳间隔没有心跳时从控制器中移除
+const staleBeats = 3
+
+// Executor 注册到控制器的执行器
+type Executor struct {
+	Name        string           `json:"name"`
+	Host        string           `json:"host"`
+	GroupName   string           `json:"group_name"`
+	Config      conf.BenchConfig `json:"executor_config"`
+	Description string           `json:"description"`
+	RegisterAt  int64            `json:"register_at"`
+	// Capabilities 执行器注册时上报的能力，旧版本执行器没有上报时为nil
+	Capabilities *utils.Capabilities `json:"capabilities,omitempty"`
+	// HeartbeatInterval 执行器的心跳间隔(s)，为0时(旧版本执行器没有心跳)不检查心跳是否超时
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+	// 以下字段由执行器心跳上报
+	LastSeen int64  `json:"last_seen"`
+	CPU      int    `json:"cpu"`
+	Version  string `json:"version"`
+	RunID    string `json:"run_id"`
+	RunState string `json:"run_state"`
+	Running  bool   `json:"running"`
+	client   perform_pb.PerformServiceClient
+	conn     *grpc.ClientConn
+}
+
+// ExecutorResult 对单个执行器操作的结果
+type ExecutorResult struct {
+	Name    string `json:"name"`
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+	RunID   string `json:"run_id,omitempty"`
+	Rate    int64  `json:"rate"`
+	// ClockOffset 控制器测得的执行器时钟偏差(us)，执行器时间减控制器时间
+	ClockOffset int64 `json:"clock_offset"`
+	// ReportedOffset 执行器上报的偏差(us)，包含单程网络延迟
+	ReportedOffset int64 `json:"reported_offset"`
+}
+
+// Controller 管理执行器并把压测操作分发到整个分组
+type Controller struct {
+	m         sync.RWMutex
+	executors map[string]*Executor
+}
+
+func New() *Controller {
+	return &Controller{executors: make(map[string]*Executor)}
+}
+
+// Add 注册执行器，同名执行器会被替换
+func (c *Controller) Add(e *Executor) error {
+	conn, err := grpc.NewClient(e.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	}
+	e.conn = conn
+	e.client = perform_pb.NewPerformServiceClient(conn)
+	e.RegisterAt = utils.GetTimeUs()
+	e.LastSeen = e.RegisterAt
+	if e.Capabilities != nil {
+		e.CPU, e.Version = e.Capabilities.CPU, e.Capabilities.Version
+	}
+	c.m.Lock()
+	old := c.executors[e.Name]
+	c.executors[e.Name] = e
+	c.m.Unlock()
+	if old != nil {
+		_ = old.conn.Close()
+	}
+	logger.Info("Executor %s(%s) registered to group %s", e.Name, e.Host, e.GroupName)
+	return nil
+}
+
+// Del 注销执行器
+func (c *Controller) Del(name string) bool {
+	c.m.Lock()
+	e, ok := c.executors[name]
+	delete(c.executors, name)
+	c.m.Unlock()
+	if ok {
+		_ = e.conn.Close()
+		logger.Info("Executor %s unregistered", name)
+	}
+	return ok
+}
+
+// Heartbeat 更新执行器心跳上报的状态，执行器未注册时返回false
+func (c *Controller) Heartbeat(hb *Executor) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	e, ok := c.executors[hb.Name]
+	if !ok || e.Host != hb.Host {
+		return false
+	}
+	e.LastSeen = utils.GetTimeUs()
+	if hb.HeartbeatInterval > 0 {
+		e.HeartbeatInterval = hb.HeartbeatInterval
+	}
+	e.CPU, e.Version = hb.CPU, hb.Version
+	e.RunID, e.RunState, e.Running = hb.RunID, hb.RunState, hb.Running
+	return true
+}
+
+// stale 执行器是否已经超过 staleBeats 个心跳间隔没有心跳，例如进程被强制杀掉没有注销
+func (e *Executor) stale(now int64) bool {
+	return e.HeartbeatInterval > 0 && now-e.LastSeen > staleBeats*e.HeartbeatInterval*1000*1000
+}
+
+// evictStale 移除心跳超时的执行器，执行器恢复后心跳返回未注册，会重新注册
+func (c *Controller) evictStale() {
+	now := utils.GetTimeUs()
+	c.m.Lock()
+	var evicted []*Executor
+	for name, e := range c.executors {
+		if e.stale(now) {
+			delete(c.executors, name)
+			evicted = append(evicted, e)
+		}
+	}
+	c.m.Unlock()
+	for _, e := range evicted {
+		_ = e.conn.Close()
+		logger.Warning("Executor %s(%s) has no heartbeat for %d s, removed", e.Name, e.Host, (now-e.LastSeen)/1000/1000)
+	}
+}
+
+// Executors 返回执行器的拷贝，group为空时返回全部，心跳超时的执行器会先被移除
+// 心跳会在锁内更新执行器的状态，拷贝在持有锁时生成，调用方可以在锁外读取和序列化
+func (c *Controller) Executors(group string) []Executor {
+	members := c.members(group)
+	c.m.RLock()
+	defer c.m.RUnlock()
+	res := make([]Executor, 0, len(members))
+	for _, e := range members {
+		res = append(res, *e)
+	}
+	return res
+}
+
+// members 返回分组内的执行器，按名称排序，只能读取注册后不再修改的字段和gRPC客户端
+func (c *Controller) members(group string) []*Executor {
+	c.evictStale()
+	c.m.RLock()
+	defer c.m.RUnlock()
+	res := make([]*Executor, 0, len(c.executors))
+	for _, e := range c.executors {
+		if group == "" || e.GroupName == group {
+			res = append(res, e)
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Name < res[b].Name
+	})
+	return res
+}
+
+// Selector 按工作器、标签和速率挑选执行器，空字段不参与过滤
+type Selector struct {
+	Group  string
+	Worker string
+	Labels map[string]string
+	// Rate 执行器推荐的最大速率不低于该值，执行器没有限制时总是满足
+	Rate int64
+}
+
+// Match 执行器是否满足挑选条件
+func (s *Selector) Match(e *Executor) bool {
+	if s.Group != "" && e.GroupName != s.Group {
+		return false
+	}
+	if s.Worker != "" && !e.Capabilities.HasWorker(s.Worker) {
+		return false
+	}
+	for k, v := range s.Labels {
+		if e.Capabilities == nil || e.Capabilities.Labels[k] != v {
+			return false
+		}
+	}
+	if s.Rate > 0 && e.Capabilities != nil && e.Capabilities.MaxRate > 0 && e.Capabilities.MaxRate < s.Rate {
+		return false
+	}
+	return true
+}
+
+// Select 返回满足条件的执行器的拷贝
+func (c *Controller) Select(s *Selector) []Executor {
+	res := make([]Executor, 0)
+	for _, e := range c.Executors(s.Group) {
+		if s.Match(&e) {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (c *Controller) group(group string) ([]*Executor, error) {
+	members := c.members(group)
+	if group == "" || len(members) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, group)
+	}
+	return members, nil
+}
+
+// split 把total平均分给n个执行器，余数分给前面的执行器
+func split(total int64, n int) []int64 {
+	res := make([]int64, n)
+	for i := range res {
+		res[i] = total / int64(n)
+		if int64(i) < total%int64(n) {
+			res[i]++
+		}
+	}
+	return res
+}
+
+// participants 参与压测的执行器个数，不超过n
+// 速率、请求数和每个阶段的速率按执行器拆分后每个执行器至少分到1，分到0的执行器会变成不限速或按时长压测
+func participants(cfg conf.BenchConfig, n int) int {
+	values := []int64{cfg.Nums}
+	for _, st := range cfg.Schedule() {
+		values = append(values, st.Rate)
+	}
+	for _, v := range values {
+		if v > 0 && v < int64(n) {
+			n = int(v)
+		}
+	}
+	return n
+}
+
+// fanOut 并发地对分组内每个执行器执行f
+func fanOut[T any](members []*Executor, f func(i int, e *Executor) T) []T {
+	res := make([]T, len(members))
+	var wait sync.WaitGroup
+	for i, e := range members {
+		wait.Add(1)
+		go func(i int, e *Executor) {
+			defer wait.Done()
+			res[i] = f(i, e)
+		}(i, e)
+	}
+	wait.Wait()
+	return res
+}
+
+// clockSamples 测量时钟偏差的采样次数
+const clockSamples = 3
+
+// measureOffset 测量执行器相对控制器的时钟偏差(us)，取往返时延最小的一次采样
+func measureOffset(e *Executor) (int64, error) {
+	var offset int64
+	bestRtt := int64(-1)
+	for i := 0; i < clockSamples; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		t0 := utils.GetTimeUs()
+		resp, err := e.client.SyncClock(ctx, &perform_pb.ClockMessage{Time: t0})
+		t1 := utils.GetTimeUs()
+		cancel()
+		if err != nil {
+			return 0, err
+		}
+		if rtt := t1 - t0; bestRtt < 0 || rtt < bestRtt {
+			bestRtt = rtt
+			offset = resp.GetTime() - (t0+t1)/2
+		}
+	}
+	return offset, nil
+}
+
+// splitConfig 把速率、请求数以及每个阶段的速率按执行器个数拆分
+func splitConfig(cfg conf.BenchConfig, n int) []conf.BenchConfig {
+	rates := split(cfg.Rate, n)
+	nums := split(cfg.Nums, n)
+	res := make([]conf.BenchConfig, n)
+	for i := range res {
+		res[i] = cfg
+		res[i].Rate = rates[i]
+		res[i].Nums = nums[i]
+		res[i].Stages = nil
+	}
+	for _, st := range cfg.Stages {
+		for i, rate := range split(st.Rate, n) {
+			res[i].Stages = append(res[i].Stages, conf.Stage{Duration: st.Duration, Rate: rate})
+		}
+	}
+	return res
+}
+
+// Start 在分组内所有执行器上启动压测，速率和请求数按执行器个数拆分
+// 速率、请求数或某个阶段的速率小于执行器个数时只在前面的执行器上启动，其余执行器不参与本次压测
+// startDelay 大于0时所有执行器先完成全局前置，在控制器时间 now+startDelay(ms) 同时开始发压，
+// 有执行器启动失败时会停止整个分组
+func (c *Controller) Start(group string, cfg conf.BenchConfig, startDelay int64) ([]ExecutorResult, error) {
+	members, err := c.group(group)
+	if err != nil {
+		return nil, err
+	}
+	if k := participants(cfg, len(members)); k < len(members) {
+		logger.Warning("Group %s has %d executors, only %d of them start to keep every rate share above 0", group, len(members), k)
+		members = members[:k]
+	}
+	cfgs := splitConfig(cfg, len(members))
+	offsets := make([]int64, len(members))
+	var startAt int64
+	if startDelay > 0 {
+		errs := fanOut(members, func(i int, e *Executor) error {
+			offset, err := measureOffset(e)
+			if err != nil {
+				return fmt.Errorf("executor %s sync clock: %w", e.Name, err)
+			}
+			offsets[i] = offset
+			return nil
+		})
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+		startAt = utils.GetTimeUs() + startDelay*1000
+	}
+	results := fanOut(members, func(i int, e *Executor) ExecutorResult {
+		ec := cfgs[i]
+		res := ExecutorResult{Name: e.Name, Rate: ec.Rate, ClockOffset: offsets[i]}
+		if startAt > 0 {
+			// 转换成执行器的时钟
+			ec.StartAt = startAt + offsets[i]
+		}
+		js, err := json.Marshal(ec)
+		if err != nil {
+			res.Code, res.Message = int32(perform_pb.ErrCode_ERR_INVALID_CONFIG), err.Error()
+			return res
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), startTimeout)
+		defer cancel()
+		resp, err := e.client.StartPerform(ctx, &perform_pb.StartMessage{Json: js, ControllerTime: utils.GetTimeUs()})
+		if err != nil {
+			res.Code, res.Message = int32(perform_pb.ErrCode_ERR_INTERNAL), err.Error()
+			return res
+		}
+		res.Code, res.Message, res.RunID = resp.GetCode(), string(resp.GetMessage()), resp.GetRunId()
+		res.ReportedOffset = resp.GetClockOffset()
+		return res
+	})
+	if startAt > 0 {
+		for _, r := range results {
+			if r.Code != 0 {
+				logger.Warning("Executor %s not ready: %s, stop group %s", r.Name, r.Message, group)
+				_, _ = c.Stop(group)
+				break
+			}
+		}
+		if late := utils.GetTimeUs() - startAt; late > 0 {
+			logger.Warning("Group %s ready %d ms after the start time", group, late/1000)
+		}
+	}
+	return results, nil
+}
+
+// Stop 停止分组内所有执行器的压测
+func (c *Controller) Stop(group string) ([]ExecutorResult, error) {
+	members, err := c.group(group)
+	if err != nil {
+		return nil, err
+	}
+	return fanOut(members, func(i int, e *Executor) ExecutorResult {
+		res := ExecutorResult{Name: e.Name}
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		resp, err := e.client.StopPerform(ctx, &perform_pb.EmptyMessage{})
+		if err != nil {
+			res.Code, res.Message = int32(perform_pb.ErrCode_ERR_INTERNAL), err.Error()
+			return res
+		}
+		res.Code = resp.GetCode()
+		return res
+	}), nil
+}
+
+// GroupStats 分组合并后的统计以及每个执行器的统计
+type GroupStats struct {
+	Group  string                  `json:"group"`
+	Merged *stat.IntervalStatistic `json:"merged"`
+	// Latency 由各执行器的直方图合并后计算，分位数是精确的
+	Latency   *stat.Summary             `json:"latency,omitempty"`
+	Executors map[string]*ExecutorStats `json:"executors"`
+	// Saturated 跟不上目标速率的执行器，这些执行器的结果反映的是执行器自身的瓶颈
+	Saturated []string `json:"saturated,omitempty"`
+}
+
+// ExecutorStats 单个执行器的统计
+type ExecutorStats struct {
+	Code    int32                   `json:"code"`
+	Message string                  `json:"message,omitempty"`
+	Stats   *stat.IntervalStatistic `json:"stats,omitempty"`
+	Latency *stat.Summary           `json:"latency,omitempty"`
+}
+
+// CollectStats 收集分组内所有执行器自上次收集以来的统计并合并，percentiles为空时使用 stat.SummaryPercentiles
+func (c *Controller) CollectStats(group string, percentiles []float64) (*GroupStats, error) {
+	if len(percentiles) == 0 {
+		percentiles = stat.SummaryPercentiles
+	}
+	members, err := c.group(group)
+	if err != nil {
+		return nil, err
+	}
+	all := fanOut(members, func(i int, e *Executor) *ExecutorStats {
+		ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+		defer cancel()
+		resp, err := e.client.CollectStats(ctx, &perform_pb.EmptyMessage{})
+		if err != nil {
+			return &ExecutorStats{Code: int32(perform_pb.ErrCode_ERR_INTERNAL), Message: err.Error()}
+		}
+		if resp.GetCode() != 0 || resp.GetStats() == nil {
+			return &ExecutorStats{Code: resp.GetCode()}
+		}
+		ss := fromPerformStats(resp.GetStats())
+		return &ExecutorStats{Stats: ss, Latency: ss.LatencySummary(percentiles)}
+	})
+	gs := &GroupStats{Group: group, Executors: make(map[string]*ExecutorStats, len(members))}
+	for i, e := range members {
+		gs.Executors[e.Name] = all[i]
+		gs.Merged = stat.Combine(gs.Merged, all[i].Stats)
+		if ss := all[i].Stats; ss != nil && ss.Generator != nil && ss.Generator.Saturated {
+			gs.Saturated = append(gs.Saturated, e.Name)
+		}
+	}
+	if len(gs.Saturated) > 0 {
+		logger.Warning("Executors can not keep up with the target rate in group %s: %v", group, gs.Saturated)
+	}
+	if gs.Merged != nil {
+		gs.Latency = gs.Merged.LatencySummary(percentiles)
+	}
+	return gs, nil
+}
+
+func fromPerformStats(ps *perform_pb.PerformStats) *stat.IntervalStatistic {
+	records := make([]stat.Record, 0, len(ps.GetLatency()))
+	for _, r := range ps.GetLatency() {
+		records = append(records, stat.Record{Key: r.GetKey(), Value: r.GetValue()})
+	}
+	var histogram *hdrhistogram.Histogram
+	if len(ps.GetHistogram()) > 0 {
+		h, err := stat.DecodeHistogram(ps.GetHistogram())
+		if err != nil {
+			logger.Error("Decode histogram err: %v", err)
+		} else {
+			histogram = h
+		}
+	}
+	var generator *stat.GeneratorStats
+	if g := ps.GetGenerator(); g != nil {
+		generator = &stat.GeneratorStats{
+			CPU:             g.GetCpu(),
+			Cores:           g.GetCores(),
+			Goroutines:      g.GetGoroutines(),
+			GCCount:         g.GetGcCount(),
+			GCPause:         g.GetGcPause(),
+			GCPauseMax:      g.GetGcPauseMax(),
+			SchedLatencyP99: g.GetSchedLatencyP99(),
+			SchedLatencyMax: g.GetSchedLatencyMax(),
+			TargetRate:      g.GetTargetRate(),
+			LimiterLag:      g.GetLimiterLag(),
+			Saturated:       g.GetSaturated(),
+		}
+	}
+	var phases map[string]*hdrhistogram.Histogram
+	for _, p := range ps.GetPhases() {
+		h, err := stat.DecodeHistogram(p.GetHistogram())
+		if err != nil {
+			logger.Error("Decode histogram of phase %s err: %v", p.GetName(), err)
+			continue
+		}
+		if phases == nil {
+			phases = make(map[string]*hdrhistogram.Histogram, len(ps.GetPhases()))
+		}
+		phases[p.GetName()] = h
+	}
+	return &stat.IntervalStatistic{
+		Metrics:         fromMetrics(ps.GetMetrics()),
+		Phases:          phases,
+		Generator:       generator,
+		Histogram:       histogram,
+		SendTotal:       ps.GetSendCount(),
+		ErrorTotal:      ps.GetErrCount(),
+		Durations:       ps.GetDuration(),
+		SendBytes:       ps.GetSendBytes(),
+		RecvBytes:       ps.GetRecvBytes(),
+		Records:         records,
+		PausedDurations: ps.GetPausedDuration(),
+		Paused:          ps.GetPaused(),
+	}
+}
+
+// fromMetrics 还原执行器上报的自定义指标，无法解码的直方图丢弃
+func fromMetrics(ms []*perform_pb.Metric) *stat.Metrics {
+	if len(ms) == 0 {
+		return nil
+	}
+	res := &stat.Metrics{}
+	for _, m := range ms {
+		switch m.GetKind() {
+		case stat.MetricCounter:
+			if res.Counters == nil {
+				res.Counters = make(map[string]int64)
+			}
+			res.Counters[m.GetName()] = int64(m.GetValue())
+		case stat.MetricGauge:
+			if res.Gauges == nil {
+				res.Gauges = make(map[string]float64)
+			}
+			res.Gauges[m.GetName()] = m.GetValue()
+		case stat.MetricHistogram:
+			h, err := stat.DecodeHistogram(m.GetHistogram())
+			if err != nil {
+				logger.Error("Decode histogram of metric %s err: %v", m.GetName(), err)
+				continue
+			}
+			if res.Histograms == nil {
+				res.Histograms = make(map[string]*hdrhistogram.Histogram)
+			}
+			res.Histograms[m.GetName()] = h
+		}
+	}
+	return res
+}
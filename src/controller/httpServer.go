@@ -0,0 +1,247 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"strconv"
+	"strings"
+)
+
+// response 控制器http接口统一的返回格式，code为0表示成功
+type response struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// addRequest 执行器注册的请求，与 utils.RegistrationUtils 的请求体一致
+type addRequest struct {
+	Name           string `json:"name"`
+	Host           string `json:"host"`
+	GroupName      string `json:"group_name"`
+	ExecutorConfig string `json:"executor_config"`
+	Description    string `json:"description"`
+	// HeartbeatInterval 执行器的心跳间隔(s)，旧版本执行器没有该字段
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+	// Capabilities 执行器的能力和资源，旧版本执行器没有该字段
+	Capabilities *utils.Capabilities `json:"capabilities"`
+}
+
+// heartbeatRequest 执行器心跳的请求，与 utils.RegistrationUtils 的心跳请求体一致
+type heartbeatRequest struct {
+	Name      string `json:"name"`
+	Host      string `json:"host"`
+	GroupName string `json:"group_name"`
+	CPU       int    `json:"cpu"`
+	Version   string `json:"version"`
+	RunID     string `json:"run_id"`
+	RunState  string `json:"run_state"`
+	Running   bool   `json:"running"`
+	// HeartbeatInterval 执行器的心跳间隔(s)
+	HeartbeatInterval int64 `json:"heartbeat_interval"`
+}
+
+type groupRequest struct {
+	Group  string           `json:"group"`
+	Config conf.BenchConfig `json:"config"`
+	// StartDelay 大于0时所有执行器在该毫秒数后同时开始发压
+	StartDelay int64 `json:"start_delay"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, msg string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(&response{Code: code, Message: msg, Data: data})
+	if err != nil {
+		logger.Error("Write response err: %v", err)
+	}
+}
+
+// post 只接受POST请求并把请求体解析到req
+func post(w http.ResponseWriter, r *http.Request, req interface{}) bool {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		writeJSON(w, -1, "method not allowed", nil)
+		return false
+	}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeJSON(w, -1, fmt.Sprintf("invalid body: %v", err), nil)
+		return false
+	}
+	return true
+}
+
+func (c *Controller) handleAdd(w http.ResponseWriter, r *http.Request) {
+	req := &addRequest{}
+	if !post(w, r, req) {
+		return
+	}
+	if req.Name == "" || req.Host == "" || req.GroupName == "" {
+		writeJSON(w, -1, "name, host and group_name are required", nil)
+		return
+	}
+	e := &Executor{
+		Name:              req.Name,
+		Host:              req.Host,
+		GroupName:         req.GroupName,
+		Description:       req.Description,
+		Capabilities:      req.Capabilities,
+		HeartbeatInterval: req.HeartbeatInterval,
+	}
+	if req.ExecutorConfig != "" {
+		if err := json.Unmarshal([]byte(req.ExecutorConfig), &e.Config); err != nil {
+			writeJSON(w, -1, fmt.Sprintf("invalid executor_config: %v", err), nil)
+			return
+		}
+	}
+	if err := c.Add(e); err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	writeJSON(w, 0, "success", nil)
+}
+
+func (c *Controller) handleDel(w http.ResponseWriter, r *http.Request) {
+	req := &addRequest{}
+	if !post(w, r, req) {
+		return
+	}
+	if !c.Del(req.Name) {
+		writeJSON(w, -1, fmt.Sprintf("executor %s not found", req.Name), nil)
+		return
+	}
+	writeJSON(w, 0, "success", nil)
+}
+
+func (c *Controller) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	req := &heartbeatRequest{}
+	if !post(w, r, req) {
+		return
+	}
+	ok := c.Heartbeat(&Executor{
+		Name:              req.Name,
+		Host:              req.Host,
+		CPU:               req.CPU,
+		Version:           req.Version,
+		RunID:             req.RunID,
+		RunState:          req.RunState,
+		Running:           req.Running,
+		HeartbeatInterval: req.HeartbeatInterval,
+	})
+	if !ok {
+		// 执行器需要重新注册
+		writeJSON(w, utils.CodeNotRegistered, fmt.Sprintf("executor %s not registered", req.Name), nil)
+		return
+	}
+	writeJSON(w, 0, "success", nil)
+}
+
+// handleList 支持按 group、worker、label(可重复，key=value)、rate 过滤执行器
+func (c *Controller) handleList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s := &Selector{Group: q.Get("group"), Worker: q.Get("worker")}
+	if v := q.Get("rate"); v != "" {
+		rate, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			writeJSON(w, -1, fmt.Sprintf("invalid rate: %v", err), nil)
+			return
+		}
+		s.Rate = rate
+	}
+	labels, err := utils.ParseLabels(strings.Join(q["label"], ","))
+	if err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	s.Labels = labels
+	writeJSON(w, 0, "success", c.Select(s))
+}
+
+func (c *Controller) handleStart(w http.ResponseWriter, r *http.Request) {
+	req := &groupRequest{}
+	if !post(w, r, req) {
+		return
+	}
+	if err := req.Config.Check(); err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	res, err := c.Start(req.Group, req.Config, req.StartDelay)
+	writeResults(w, res, err)
+}
+
+func (c *Controller) handleStop(w http.ResponseWriter, r *http.Request) {
+	req := &groupRequest{}
+	if !post(w, r, req) {
+		return
+	}
+	res, err := c.Stop(req.Group)
+	writeResults(w, res, err)
+}
+
+// writeResults 有任意一个执行器失败时code为-1，data中带有每个执行器的结果
+func writeResults(w http.ResponseWriter, res []ExecutorResult, err error) {
+	if err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	for _, v := range res {
+		if v.Code != 0 {
+			writeJSON(w, -1, fmt.Sprintf("executor %s failed: %s", v.Name, v.Message), res)
+			return
+		}
+	}
+	writeJSON(w, 0, "success", res)
+}
+
+// handleStats percentiles 参数指定合并后输出的分位数，例如 50,99.9,max
+func (c *Controller) handleStats(w http.ResponseWriter, r *http.Request) {
+	percentiles, err := stat.ParsePercentiles(r.URL.Query().Get("percentiles"))
+	if err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	gs, err := c.CollectStats(r.URL.Query().Get("group"), percentiles)
+	if err != nil {
+		writeJSON(w, -1, err.Error(), nil)
+		return
+	}
+	writeJSON(w, 0, "success", gs)
+}
+
+// Handler 返回控制器的http路由
+func (c *Controller) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/executor/add", c.handleAdd)
+	mux.HandleFunc("/v1/executor/del", c.handleDel)
+	mux.HandleFunc("/v1/executor/list", c.handleList)
+	mux.HandleFunc("/v1/executor/heartbeat", c.handleHeartbeat)
+	mux.HandleFunc("/v1/group/start", c.handleStart)
+	mux.HandleFunc("/v1/group/stop", c.handleStop)
+	mux.HandleFunc("/v1/group/stats", c.handleStats)
+	return mux
+}
+
+var httpServer *http.Server
+
+// StartHttpServer 启动控制器的http服务，阻塞直到服务停止
+func StartHttpServer(addr string, c *Controller) error {
+	httpServer = &http.Server{Addr: addr, Handler: c.Handler()}
+	logger.Info("Starting controller on %s", addr)
+	err := httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+func StopHttpServer() {
+	if httpServer != nil {
+		_ = httpServer.Close()
+	}
+}
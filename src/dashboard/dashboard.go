@@ -0,0 +1,331 @@
+package dashboard
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	// historyLen 迷你图保留的区间个数
+	historyLen = 60
+	// logLines 面板中显示的日志行数
+	logLines = 6
+	// maxLogBuffer 仪表盘运行期间缓存的日志行数，退出后重新打印
+	maxLogBuffer = 1000
+	// maxErrorRows 面板中显示的错误种类数
+	maxErrorRows = 5
+)
+
+// Supported 标准输出是否为终端，不是终端时使用普通的日志输出
+func Supported() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// Dashboard 本地压测时的终端仪表盘，每秒刷新
+type Dashboard struct {
+	r   *runner.BenchMarkRunner
+	cfg conf.BenchConfig
+	out io.Writer
+	// cancel 第二次按 q 时强制退出
+	cancel context.CancelFunc
+
+	m    sync.Mutex
+	logs []string
+	// 以下字段只在刷新协程中访问
+	last    *stat.IntervalStatistic
+	rps     []float64
+	p99     []float64
+	errors  map[string]int64
+	prevN   int64
+	prevErr int64
+	notice  string
+	// quits 只在读取按键的协程中访问
+	quits int
+}
+
+// New 创建仪表盘，按 q 时停止压测，再按一次时调用cancel强制退出
+func New(r *runner.BenchMarkRunner, cfg conf.BenchConfig, cancel context.CancelFunc) *Dashboard {
+	return &Dashboard{r: r, cfg: cfg, out: os.Stdout, cancel: cancel, errors: make(map[string]int64)}
+}
+
+// Write 收集日志，在面板中显示最近几行
+func (d *Dashboard) Write(p []byte) (int, error) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		d.logs = append(d.logs, line)
+	}
+	if len(d.logs) > maxLogBuffer {
+		d.logs = d.logs[len(d.logs)-maxLogBuffer:]
+	}
+	return len(p), nil
+}
+
+// Run 接管终端直到ctx取消，退出后恢复终端并重新打印期间的日志
+func (d *Dashboard) Run(ctx context.Context) {
+	d.r.SetQuiet(true)
+	logger.SetOutput(d)
+	restore := func() {}
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		if st, err := term.MakeRaw(fd); err == nil {
+			keys, err := openKeys()
+			if err == nil {
+				go d.readKeys(keys)
+			}
+			restore = func() {
+				// 关闭后读取按键的协程随之退出
+				if keys != nil {
+					_ = keys.Close()
+				}
+				_ = term.Restore(fd, st)
+			}
+		}
+	}
+	// 隐藏光标
+	_, _ = fmt.Fprint(d.out, "\x1b[?25l")
+	defer func() {
+		_, _ = fmt.Fprint(d.out, "\x1b[?25h\x1b[H\x1b[2J")
+		restore()
+		logger.SetOutput(os.Stdout)
+		d.r.SetQuiet(false)
+		d.m.Lock()
+		for _, l := range d.logs {
+			_, _ = fmt.Fprintln(os.Stdout, l)
+		}
+		d.m.Unlock()
+	}()
+	sub := d.r.SubscribeStatistics(1)
+	defer d.r.Unsubscribe(sub)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	d.draw()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ss := <-sub.C:
+			d.update(ss)
+		case <-ticker.C:
+		}
+		d.draw()
+	}
+}
+
+// readKeys 处理按键：q/Ctrl+C 停止，p 暂停/恢复，+/- 调整速率
+func (d *Dashboard) readKeys(keys io.Reader) {
+	buf := make([]byte, 1)
+	for {
+		n, err := keys.Read(buf)
+		if err != nil || n == 0 {
+			return
+		}
+		switch buf[0] {
+		case 'q', 3:
+			// 与 Ctrl+C 信号的处理一致，第二次强制退出
+			d.quits++
+			if d.quits > 1 {
+				d.setNotice("force exit")
+				d.cancel()
+				return
+			}
+			d.setNotice("stopping, press q again to force exit")
+			// 停止时会等待执行中的请求完成，不阻塞按键的读取
+			go d.r.Stop()
+		case 'p':
+			var err error
+			if d.r.IsPaused() {
+				err = d.r.Resume()
+			} else {
+				err = d.r.Pause()
+			}
+			if err != nil {
+				d.setNotice(err.Error())
+			}
+		case '+', '=':
+			d.adjustRate(1.1)
+		case '-', '_':
+			d.adjustRate(0.9)
+		}
+	}
+}
+
+func (d *Dashboard) adjustRate(factor float64) {
+	rate := d.r.Rate()
+	if rate == 0 {
+		d.setNotice("rate is unlimited, can not adjust")
+		return
+	}
+	next := int64(float64(rate) * factor)
+	if next == rate {
+		next = rate + 1
+		if factor < 1 {
+			next = rate - 1
+		}
+	}
+	if err := d.r.SetRate(max(next, 1)); err != nil {
+		d.setNotice(err.Error())
+	}
+}
+
+func (d *Dashboard) setNotice(msg string) {
+	d.m.Lock()
+	d.notice = msg
+	d.m.Unlock()
+}
+
+// update 记录新的区间统计
+func (d *Dashboard) update(ss *stat.IntervalStatistic) {
+	n := ss.SendTotal + ss.ErrorTotal
+	active := ss.Durations - ss.PausedDurations
+	rps := 0.0
+	if active > 0 && n >= d.prevN {
+		rps = float64(n-d.prevN) / (float64(active) / 1000 / 1000)
+	}
+	d.prevN, d.prevErr = n, ss.ErrorTotal
+	var p99 float64
+	if ss.Histogram != nil {
+		p99 = float64(ss.Histogram.ValueAtPercentile(99))
+	}
+	d.rps = appendHistory(d.rps, rps)
+	d.p99 = appendHistory(d.p99, p99)
+	for k, v := range ss.Errors {
+		d.errors[k] += v
+	}
+	d.last = ss
+}
+
+func appendHistory(h []float64, v float64) []float64 {
+	h = append(h, v)
+	if len(h) > historyLen {
+		h = h[len(h)-historyLen:]
+	}
+	return h
+}
+
+// draw 重绘整个面板，终端处于raw模式时换行需要\r\n
+func (d *Dashboard) draw() {
+	var sb strings.Builder
+	line := func(format string, v ...interface{}) {
+		sb.WriteString(fmt.Sprintf(format, v...))
+		sb.WriteString("\x1b[K\r\n")
+	}
+	sb.WriteString("\x1b[H")
+	state, id, elapsed := "starting", "-", int64(0)
+	if run := d.r.CurrentRun(); run != nil {
+		state, id = run.State.String(), run.ID
+		elapsed = (utils.GetTimeUs() - run.StartAt) / 1000 / 1000
+	}
+	if d.r.IsPaused() {
+		state += " (paused)"
+	}
+	total := (time.Duration(d.cfg.TotalDuration()) * time.Second).String()
+	if d.cfg.Nums > 0 {
+		total = fmt.Sprintf("%d reqs", d.cfg.Nums)
+	}
+	line("Run %s  %s  elapsed %s / %s", id, state, time.Duration(elapsed)*time.Second, total)
+	line("Worker %s  workers %d/%d  in-flight %d", d.cfg.WorkerName, d.r.ActiveWorkers(), d.cfg.Workers, d.r.InFlight())
+	line("")
+	target := "unlimited"
+	if rate := d.r.Rate(); rate > 0 {
+		target = fmt.Sprintf("%d", rate)
+	}
+	cur := 0.0
+	if len(d.rps) > 0 {
+		cur = d.rps[len(d.rps)-1]
+	}
+	line("RPS      current %-10.1f target %-10s %s", cur, target, sparkline(d.rps))
+	if ss := d.last; ss != nil && ss.Histogram != nil && ss.Histogram.TotalCount() > 0 {
+		h := ss.Histogram
+		percentiles := d.cfg.ReportPercentiles()
+		parts := make([]string, 0, len(percentiles))
+		for _, p := range percentiles {
+			parts = append(parts, fmt.Sprintf("%s %s", stat.PercentileName(p), stat.FormatLatency(h.ValueAtPercentile(p))))
+		}
+		line("Latency  %s", strings.Join(parts, "  "))
+	} else {
+		line("Latency  -")
+	}
+	line("P99      %s", sparkline(d.p99))
+	if ss := d.last; ss != nil && ss.Generator != nil {
+		g := ss.Generator
+		saturated := ""
+		if g.Saturated {
+			saturated = "  !!! can not keep up with the target rate"
+		}
+		line("Self     cpu %.1f%%  goroutines %d  gc pause %s  sched p99 %s  lag %s%s", g.CPU, g.Goroutines,
+			stat.FormatLatency(g.GCPause), stat.FormatLatency(g.SchedLatencyP99), stat.FormatLatency(g.LimiterLag), saturated)
+	} else {
+		line("Self     -")
+	}
+	line("")
+	errRate := 0.0
+	if d.prevN > 0 {
+		errRate = float64(d.prevErr) / float64(d.prevN) * 100
+	}
+	line("Errors   total %d (%.2f%%)", d.prevErr, errRate)
+	kinds := make([]string, 0, len(d.errors))
+	for k := range d.errors {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return d.errors[kinds[i]] > d.errors[kinds[j]]
+	})
+	for i := 0; i < maxErrorRows; i++ {
+		if i < len(kinds) {
+			line("  %8d  %s", d.errors[kinds[i]], kinds[i])
+		} else {
+			line("")
+		}
+	}
+	line("")
+	d.m.Lock()
+	logs := d.logs
+	if len(logs) > logLines {
+		logs = logs[len(logs)-logLines:]
+	}
+	for i := 0; i < logLines; i++ {
+		if i < len(logs) {
+			line("%s", logs[i])
+		} else {
+			line("")
+		}
+	}
+	notice := d.notice
+	d.m.Unlock()
+	line("")
+	line("[q] stop  [p] pause/resume  [+/-] rate ±10%%  %s", notice)
+	sb.WriteString("\x1b[J")
+	_, _ = io.WriteString(d.out, sb.String())
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline 按最大值归一化绘制迷你图
+func sparkline(values []float64) string {
+	top := 0.0
+	for _, v := range values {
+		top = max(top, v)
+	}
+	rs := make([]rune, 0, len(values))
+	for _, v := range values {
+		i := 0
+		if top > 0 {
+			i = int(v / top * float64(len(sparks)-1))
+		}
+		rs = append(rs, sparks[i])
+	}
+	return string(rs)
+}
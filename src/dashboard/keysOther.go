@@ -0,0 +1,13 @@
+//go:build !unix
+
+package dashboard
+
+import (
+	"io"
+	"os"
+)
+
+// openKeys 直接读取stdin，无法中断阻塞中的Read，压测结束后读取按键的协程在下一次按键时退出
+func openKeys() (io.ReadCloser, error) {
+	return io.NopCloser(os.Stdin), nil
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package dashboard
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// keys 以非阻塞方式打开的stdin副本，由runtime轮询，关闭时阻塞中的Read立即返回
+type keys struct {
+	*os.File
+}
+
+// openKeys 复制stdin用于读取按键
+func openKeys() (io.ReadCloser, error) {
+	fd, err := syscall.Dup(int(os.Stdin.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.SetNonblock(fd, true); err != nil {
+		_ = syscall.Close(fd)
+		return nil, err
+	}
+	return &keys{File: os.NewFile(uintptr(fd), "stdin")}, nil
+}
+
+// Close 关闭副本，并恢复与副本共享状态的stdin为阻塞模式
+func (k *keys) Close() error {
+	err := k.File.Close()
+	_ = syscall.SetNonblock(syscall.Stdin, false)
+	return err
+}
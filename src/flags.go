@@ -0,0 +1,298 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/store"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// secondsFlag 以秒为单位的参数，可以写整数秒，也可以写 30s、10m、1h
+type secondsFlag struct {
+	v *int64
+}
+
+func (f secondsFlag) String() string {
+	if f.v == nil {
+		return "0"
+	}
+	return strconv.FormatInt(*f.v, 10)
+}
+
+func (f secondsFlag) Set(s string) error {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*f.v = v
+		return nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, want e.g. 30 or 30s, 10m", s)
+	}
+	if d%time.Second != 0 {
+		return fmt.Errorf("duration %q must be whole seconds", s)
+	}
+	*f.v = int64(d / time.Second)
+	return nil
+}
+
+// rateFlag 每秒请求数，可以写 500、1.5k、2M，也可以带单位 6000/m、100/s
+type rateFlag struct {
+	v *int64
+}
+
+func (f rateFlag) String() string {
+	if f.v == nil {
+		return "0"
+	}
+	return strconv.FormatInt(*f.v, 10)
+}
+
+func (f rateFlag) Set(s string) error {
+	v, err := parseRate(s)
+	if err != nil {
+		return err
+	}
+	*f.v = v
+	return nil
+}
+
+// parseRate 解析速率，返回每秒请求数
+func parseRate(s string) (int64, error) {
+	num, unit, _ := strings.Cut(s, "/")
+	per := 1.0
+	switch unit {
+	case "", "s":
+	case "m":
+		per = 60
+	case "h":
+		per = 3600
+	default:
+		return 0, fmt.Errorf("invalid rate unit %q, want /s, /m or /h", unit)
+	}
+	mul := 1.0
+	switch {
+	case strings.HasSuffix(num, "k"), strings.HasSuffix(num, "K"):
+		mul, num = 1e3, num[:len(num)-1]
+	case strings.HasSuffix(num, "M"):
+		mul, num = 1e6, num[:len(num)-1]
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid rate %q, want e.g. 500, 1.5k or 6000/m", s)
+	}
+	rate := int64(v * mul / per)
+	if v > 0 && rate == 0 {
+		return 0, fmt.Errorf("rate %q is less than 1 req/s", s)
+	}
+	return rate, nil
+}
+
+// percentilesFlag 分位数列表，例如 50,90,p99.9,max
+type percentilesFlag struct {
+	v *[]float64
+}
+
+func (f percentilesFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+	names := make([]string, 0, len(*f.v))
+	for _, p := range *f.v {
+		names = append(names, stat.PercentileName(p))
+	}
+	return strings.Join(names, ",")
+}
+
+func (f percentilesFlag) Set(s string) error {
+	ps, err := stat.ParsePercentiles(s)
+	if err != nil {
+		return err
+	}
+	*f.v = ps
+	return nil
+}
+
+// listFlag 可以重复指定，也可以用逗号分隔的参数
+type listFlag struct {
+	v *[]string
+}
+
+func (f listFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+	return strings.Join(*f.v, ",")
+}
+
+func (f listFlag) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f.v = append(*f.v, v)
+		}
+	}
+	return nil
+}
+
+// addRunFlags 注册压测相关的参数，c中已有的值作为默认值
+func addRunFlags(fs *flag.FlagSet, c *conf.BenchConfig) {
+	fs.Int64Var(&c.Workers, "w", c.Workers, "Number of workers")
+	fs.Var(secondsFlag{&c.Duration}, "d", "Test `duration`, e.g. 600, 30s, 10m")
+	fs.Var(secondsFlag{&c.Timeout}, "t", "Request timeout `duration`, e.g. 30, 5s")
+	fs.Var(rateFlag{&c.Rate}, "r", "Request `rate` per second, e.g. 500, 1.5k, 6000/m, 0 for unlimited")
+	fs.Int64Var(&c.Nums, "s", c.Nums, "Request nums")
+	fs.BoolVar(&c.PError, "p", c.PError, "Print error details or not")
+	fs.Var(secondsFlag{&c.StatInterval}, "i", "Statistics interval `duration`, e.g. 1s")
+	fs.Var(secondsFlag{&c.ReportInterval}, "report", "Console report interval `duration`, e.g. 30s, 0 for the default 30s")
+	fs.Var(percentilesFlag{&c.Percentiles}, "percentiles", "Reported `percentiles`, e.g. 50,90,99,p99.9,max, default 50,90,95,99")
+	fs.Var(secondsFlag{&c.DrainTimeout}, "g", "Grace period `duration` to drain in-flight requests on stop, e.g. 5s")
+	fs.StringVar(&c.WorkerName, "n", c.WorkerName, "Executor worker name")
+	fs.StringVar(&c.WorkerConfig, "c", c.WorkerConfig, "Worker config value")
+	fs.StringVar(&traceCfg.Endpoint, "trace", "", "Export sampled request spans to an OTLP/HTTP `endpoint` (http://127.0.0.1:4318) or an OTLP JSON file, empty to disable")
+	fs.Float64Var(&traceCfg.Sample, "trace-sample", 0, "Trace sample `ratio` in (0, 1], 0 for the default 0.01")
+	fs.StringVar(&c.Hlog, "hlog", c.Hlog, "Write interval latency histograms to an HdrHistogram log at the `path`, {run_id} is replaced, empty to disable")
+	fs.StringVar(&reqLogCfg.Path, "request-log", "", "Write raw per-request samples to the `path`, .bin for binary, others for JSONL, .gz to compress, {run_id} is replaced, empty to disable")
+	fs.Float64Var(&reqLogCfg.Sample, "request-log-sample", 0, "Request log sample `ratio` in (0, 1], 0 to log all requests")
+	fs.Var(listFlag{&c.Sinks}, "sink", "Push interval stats and the summary to the sink `url`, e.g. statsd://127.0.0.1:8125, graphite://127.0.0.1:2003, repeatable")
+	fs.StringVar(&storeDir, "store", os.Getenv(store.DirEnv), "Save run results to the result store in the `dir`, default $"+store.DirEnv+", empty to disable")
+	fs.StringVar(&targetVersion, "target-version", "", "`Version` of the system under test saved with the result, default the target_version tag")
+	fs.StringVar(&gitSHA, "git-sha", store.GitSHAFromEnv(), "Git `commit` of the code under test saved with the result, default $GIT_SHA, $GITHUB_SHA or $CI_COMMIT_SHA")
+}
+
+var (
+	// storeDir -store 参数，结果库目录
+	storeDir string
+	// targetVersion -target-version 参数
+	targetVersion string
+	// gitSHA -git-sha 参数
+	gitSHA string
+)
+
+// traceCfg -trace、-trace-sample 参数
+var traceCfg conf.TraceConf
+
+// applyTrace 把命令行中显式指定的链路追踪参数覆盖到c
+func applyTrace(fs *flag.FlagSet, c *conf.BenchConfig) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "trace" && f.Name != "trace-sample" {
+			return
+		}
+		t := conf.TraceConf{}
+		if c.Trace != nil {
+			t = *c.Trace
+		}
+		if f.Name == "trace" {
+			t.Endpoint = traceCfg.Endpoint
+		} else {
+			t.Sample = traceCfg.Sample
+		}
+		c.Trace = &t
+	})
+}
+
+// reqLogCfg -request-log、-request-log-sample 参数
+var reqLogCfg conf.RequestLogConf
+
+// applyRequestLog 把命令行中显式指定的请求日志参数覆盖到c
+func applyRequestLog(fs *flag.FlagSet, c *conf.BenchConfig) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "request-log" && f.Name != "request-log-sample" {
+			return
+		}
+		r := conf.RequestLogConf{}
+		if c.RequestLog != nil {
+			r = *c.RequestLog
+		}
+		if f.Name == "request-log" {
+			r.Path = reqLogCfg.Path
+		} else {
+			r.Sample = reqLogCfg.Sample
+		}
+		c.RequestLog = &r
+	})
+}
+
+// addServeFlags 注册执行器服务相关的参数
+func addServeFlags(fs *flag.FlagSet, c *conf.BenchConfig) {
+	fs.IntVar(&c.GrpcCfg.Port, "P", c.GrpcCfg.Port, "Grpc server port")
+	fs.StringVar(&c.GrpcCfg.RegistrationCtEndpoint, "R", c.GrpcCfg.RegistrationCtEndpoint, "The remote controller endpoints, separated by comma")
+	fs.Var(secondsFlag{&c.GrpcCfg.HeartbeatInterval}, "H", "Heartbeat interval `duration` to the controller, e.g. 5s")
+	fs.Var(secondsFlag{&c.GrpcCfg.SafetyStop}, "S", "Stop the running benchmark after losing the controller for the `duration`, 0 to disable")
+	fs.StringVar(&c.GrpcCfg.GroupName, "G", c.GrpcCfg.GroupName, "Executor group name")
+	fs.StringVar(&c.GrpcCfg.Name, "N", c.GrpcCfg.Name, "Executor name")
+	fs.StringVar(&c.GrpcCfg.LocalIp, "L", c.GrpcCfg.LocalIp, "Local IP address")
+	fs.Var(rateFlag{&maxRate}, "M", "Max recommended `rate` of the executor, 0 means unlimited")
+	fs.StringVar(&labels, "labels", labels, "Executor labels, e.g. zone=a,rack=r1")
+	fs.StringVar(&adminAddr, "admin", adminAddr, "Admin http listen `address` serving pprof, runtime stats, config and run state, e.g. 127.0.0.1:6060, empty to disable")
+}
+
+// addLogFlags 注册日志相关的参数
+func addLogFlags(fs *flag.FlagSet, o *logger.Options) {
+	fs.StringVar(&o.Level, "log-level", "info", "Log `level`: debug, info, warn or error")
+	fs.StringVar(&o.Format, "log-format", logger.FormatConsole, "Log `format`: console or json")
+	fs.StringVar(&o.File, "log-file", "", "Write logs to the `file` instead of stdout")
+	fs.Int64Var(&o.MaxSize, "log-max-size", 100, "Rotate the log file when it exceeds the `size` in MB, 0 to disable")
+	fs.IntVar(&o.MaxBackups, "log-max-backups", 5, "Number of rotated log files to keep")
+}
+
+// defaultConfig 命令行参数的默认值
+func defaultConfig() conf.BenchConfig {
+	return conf.BenchConfig{
+		Workers:      10,
+		Duration:     600,
+		Timeout:      30,
+		Rate:         500,
+		WorkerConfig: "{}",
+		DrainTimeout: 5,
+		StatInterval: 1,
+		GrpcCfg: conf.GrpcConf{
+			Port:              5052,
+			HeartbeatInterval: 5,
+			SafetyStop:        60,
+		},
+	}
+}
+
+// overrideFlags 把命令行中显式指定的压测参数覆盖到dst
+func overrideFlags(fs *flag.FlagSet, dst *conf.BenchConfig, src conf.BenchConfig) {
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "w":
+			dst.Workers = src.Workers
+		case "d":
+			dst.Duration = src.Duration
+			// 显式指定时长时不再按阶段发压
+			dst.Stages = nil
+		case "t":
+			dst.Timeout = src.Timeout
+		case "r":
+			dst.Rate = src.Rate
+			dst.Stages = nil
+		case "s":
+			dst.Nums = src.Nums
+		case "p":
+			dst.PError = src.PError
+		case "i":
+			dst.StatInterval = src.StatInterval
+		case "g":
+			dst.DrainTimeout = src.DrainTimeout
+		case "n":
+			dst.WorkerName = src.WorkerName
+		case "c":
+			dst.WorkerConfig = src.WorkerConfig
+		case "report":
+			dst.ReportInterval = src.ReportInterval
+		case "percentiles":
+			dst.Percentiles = src.Percentiles
+		case "hlog":
+			dst.Hlog = src.Hlog
+		case "sink":
+			dst.Sinks = src.Sinks
+		}
+	})
+}
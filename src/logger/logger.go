@@ -2,32 +2,154 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"runtime"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/rs/zerolog"
 )
 
 var (
-	log  *zerolog.Logger
-	once sync.Once
+	log atomic.Pointer[zerolog.Logger]
+	out switchWriter
+	// m 保护下面的配置，修改后重新创建日志实例
+	m      sync.Mutex
+	opts   = Options{Level: "info", Format: FormatConsole}
+	file   *rotateWriter
+	fields = map[string]string{}
+	// jsonFormat 当前是否为json格式，打印日志时不加锁读取
+	jsonFormat atomic.Bool
 )
 
+// 日志格式
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
+// Options 日志配置
+type Options struct {
+	// Level 日志级别 debug|info|warn|error
+	Level string
+	// Format 日志格式 console|json
+	Format string
+	// File 日志文件，为空时输出到标准输出
+	File string
+	// MaxSize 日志文件超过该大小(MB)时切割，0表示不切割
+	MaxSize int64
+	// MaxBackups 切割后保留的旧文件个数
+	MaxBackups int
+}
+
+// switchWriter 可以在运行中切换输出位置的Writer
+type switchWriter struct {
+	w atomic.Pointer[io.Writer]
+}
+
+func (s *switchWriter) Write(p []byte) (int, error) {
+	return (*s.w.Load()).Write(p)
+}
+
+// SetOutput 切换控制台日志的输出位置，例如终端仪表盘运行时把日志收集到面板中，输出到日志文件时不受影响
+func SetOutput(w io.Writer) {
+	out.w.Store(&w)
+}
+
 // init 初始化日志实例
 func init() {
-	once.Do(func() {
-		// 创建一个 ConsoleWriter
-		consoleWriter := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "2006-01-02 15:04:05"}
-		// 创建一个新的日志实例，使用 ConsoleWriter
-		logger := zerolog.New(consoleWriter).With().Timestamp().Logger()
+	SetOutput(os.Stdout)
+	rebuild()
+}
 
-		log = &logger
-	})
+// Configure 按配置重新创建日志实例
+func Configure(o Options) error {
+	if o.Level == "" {
+		o.Level = "info"
+	}
+	if o.Format == "" {
+		o.Format = FormatConsole
+	}
+	switch o.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("invalid log level %q, must be debug, info, warn or error", o.Level)
+	}
+	if o.Format != FormatConsole && o.Format != FormatJSON {
+		return fmt.Errorf("invalid log format %q, must be %s or %s", o.Format, FormatConsole, FormatJSON)
+	}
+	if o.MaxSize < 0 || o.MaxBackups < 0 {
+		return fmt.Errorf("invalid log rotation: max size %d MB, max backups %d", o.MaxSize, o.MaxBackups)
+	}
+	var f *rotateWriter
+	if o.File != "" {
+		var err error
+		f, err = openRotateWriter(o.File, o.MaxSize*1024*1024, o.MaxBackups)
+		if err != nil {
+			return err
+		}
+	}
+	m.Lock()
+	old := file
+	opts, file = o, f
+	rebuildLocked()
+	m.Unlock()
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// SetField 设置每行日志都带上的字段，例如 run_id、executor、worker，value为空时删除该字段
+func SetField(key, value string) {
+	m.Lock()
+	defer m.Unlock()
+	if value == "" {
+		delete(fields, key)
+	} else {
+		fields[key] = value
+	}
+	rebuildLocked()
+}
+
+func rebuild() {
+	m.Lock()
+	defer m.Unlock()
+	rebuildLocked()
+}
+
+// rebuildLocked 按当前配置和字段创建日志实例，调用方持有m
+func rebuildLocked() {
+	var w io.Writer = &out
+	if file != nil {
+		w = file
+	}
+	if opts.Format == FormatConsole {
+		w = zerolog.ConsoleWriter{Out: w, TimeFormat: "2006-01-02 15:04:05", NoColor: file != nil}
+	}
+	level, _ := zerolog.ParseLevel(opts.Level)
+	ctx := zerolog.New(w).Level(level).With().Timestamp()
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		ctx = ctx.Str(k, fields[k])
+	}
+	logger := ctx.Logger()
+	jsonFormat.Store(opts.Format == FormatJSON)
+	log.Store(&logger)
 }
 
 // 打印信息日志，带有自定义格式
 func baseMsg(msg string, e *zerolog.Event, v ...interface{}) {
+	if e == nil {
+		return
+	}
 	// 获取调用者信息
 	_, file, line, ok := runtime.Caller(2) // 获取调用 Info 的文件和行号
 	if !ok {
@@ -35,27 +157,31 @@ func baseMsg(msg string, e *zerolog.Event, v ...interface{}) {
 		line = 0
 	}
 	// 格式化日志消息
-	formattedMsg := fmt.Sprintf(msg, v...)
-	// 打印自定义格式的日志，包含调用者信息
+	formattedMsg := strings.TrimRight(fmt.Sprintf(msg, v...), "\n")
+	// json格式把调用者信息放在单独的字段中，控制台格式保持原来的前缀
+	if jsonFormat.Load() {
+		e.Str("caller", fmt.Sprintf("%s:%d", file, line)).Msg(formattedMsg)
+		return
+	}
 	e.Msgf("%s:%d] %s", file, line, formattedMsg)
 }
 
 func Info(msg string, v ...interface{}) {
-	baseMsg(msg, log.Info(), v...)
+	baseMsg(msg, log.Load().Info(), v...)
 }
 
 func Warning(msg string, v ...interface{}) {
-	baseMsg(msg, log.Warn(), v...)
+	baseMsg(msg, log.Load().Warn(), v...)
 }
 
 func Error(msg string, v ...interface{}) {
-	baseMsg(msg, log.Error(), v...)
+	baseMsg(msg, log.Load().Error(), v...)
 }
 
 func Debug(msg string, v ...interface{}) {
-	baseMsg(msg, log.Debug(), v)
+	baseMsg(msg, log.Load().Debug(), v...)
 }
 
 func Fatal(msg string, v ...interface{}) {
-	baseMsg(msg, log.Fatal(), v)
+	baseMsg(msg, log.Load().Fatal(), v...)
 }
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rotateWriter 按大小切割的日志文件，超过maxSize时把当前文件改名为 file.1，旧的依次后移，最多保留backups个
+type rotateWriter struct {
+	m       sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	f       *os.File
+	size    int64
+}
+
+func openRotateWriter(path string, maxSize int64, backups int) (*rotateWriter, error) {
+	w := &rotateWriter{path: path, maxSize: maxSize, backups: backups}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotateWriter) open() error {
+	f, size, err := openAppend(w.path)
+	if err != nil {
+		return err
+	}
+	w.f, w.size = f, size
+	return nil
+}
+
+// openAppend 以追加方式打开日志文件，返回文件当前的大小
+func openAppend(path string) (*os.File, int64, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("open log file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, fmt.Errorf("open log file: %w", err)
+	}
+	return f, info.Size(), nil
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	if w.maxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			// 切割失败时继续写当前文件，不丢日志，再写满 maxSize 后重试
+			_, _ = fmt.Fprintf(os.Stderr, "rotate log file %s err: %v\n", w.path, err)
+			w.size = 0
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate 旧文件依次后移，新文件打开成功后才替换当前文件，失败时当前文件保持打开
+func (w *rotateWriter) rotate() error {
+	if w.backups == 0 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		for i := w.backups - 1; i > 0; i-- {
+			src := fmt.Sprintf("%s.%d", w.path, i)
+			if _, err := os.Stat(src); err == nil {
+				if err := os.Rename(src, fmt.Sprintf("%s.%d", w.path, i+1)); err != nil {
+					return err
+				}
+			}
+		}
+		// windows 上不能改名打开中的文件，此时继续写当前文件
+		if err := os.Rename(w.path, w.path+".1"); err != nil {
+			return err
+		}
+	}
+	f, size, err := openAppend(w.path)
+	if err != nil {
+		// 当前文件已经改名或删除，句柄仍然有效，日志继续写入其中
+		return err
+	}
+	_ = w.f.Close()
+	w.f, w.size = f, size
+	return nil
+}
+
+func (w *rotateWriter) Close() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
@@ -1,150 +1,131 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
-	"os/signal"
 	"perform-cli-framework-go/src/conf"
 	"perform-cli-framework-go/src/logger"
-	"perform-cli-framework-go/src/runner"
-	"perform-cli-framework-go/src/service"
-	"perform-cli-framework-go/src/utils"
-	"perform-cli-framework-go/src/worker"
 	"runtime"
-	"syscall"
+	"strings"
 )
 
-var cfg conf.BenchConfig
-var pressCount int
+// 进程退出码
+const (
+	exitOK = 0
+	// exitError 运行出错
+	exitError = 1
+	// exitUsage 参数或配置错误，与 flag 包解析失败时一致
+	exitUsage = 2
+	// exitThreshold 场景条件不满足
+	exitThreshold = 3
+	// exitRegression compare 发现指标回退
+	exitRegression = 4
+)
 
-// parseArg 解析命令行参数
-func parseArg() error {
-	// 定义支持短参数的命令行参数
-	flag.Int64Var(&cfg.Workers, "w", 10, "Number of workers")
-	flag.Int64Var(&cfg.Duration, "d", 600, "Test duration in seconds")
-	flag.Int64Var(&cfg.Timeout, "t", 30, "Request timeout in seconds")
-	flag.Int64Var(&cfg.Rate, "r", 500, "Request rate per second")
-	flag.Int64Var(&cfg.Nums, "s", 0, "Request nums")
-	flag.BoolVar(&cfg.PError, "p", false, "Print error details or not")
-	flag.BoolVar(&cfg.GrpcCfg.Enable, "D", false, "Start with grpc server")
-	flag.IntVar(&cfg.GrpcCfg.Port, "P", 5052, "Grpc server port")
-	flag.StringVar(&cfg.GrpcCfg.RegistrationCtEndpoint, "R", "", "The remote controller endpoint")
-	flag.StringVar(&cfg.GrpcCfg.GroupName, "G", "", "Executor group name")
-	flag.StringVar(&cfg.WorkerName, "n", "", "Executor worker name")
-	flag.StringVar(&cfg.GrpcCfg.Name, "N", "", "Executor name")
-	flag.StringVar(&cfg.GrpcCfg.LocalIp, "L", "", "Local IP address")
-	flag.StringVar(&cfg.WorkerConfig, "c", "{}", "Worker config value")
-	flag.BoolVar(&cfg.ListWorker, "list_worker", false, "Print supported workers")
-	flag.Parse()
-	if cfg.ListWorker {
-		for wN, w := range worker.GetAllWorkers() {
-			// 获取默认配置的 JSON 字符串
-			jsonConfig := w.DefaultConfig()
+var cfg conf.BenchConfig
+var maxRate int64
+var labels string
+var logOpts logger.Options
+var adminAddr string
 
-			// 定义一个变量来存储格式化后的 JSON
-			var prettyJSON string
-			var jsonData interface{}
+// command 子命令
+type command struct {
+	name string
+	desc string
+	run  func(args []string) int
+}
 
-			// 将 JSON 字符串解码为一个通用接口
-			err := json.Unmarshal([]byte(jsonConfig), &jsonData)
-			if err != nil {
-				logger.Error("Error unmarshalling JSON: %v", err)
-				continue
-			}
+var commands = []command{
+	{"run", "Run a local benchmark from flags or a scenario file", cmdRun},
+	{"serve", "Start as an executor serving gRPC and register to the controller", cmdServe},
+	{"workers", "List supported workers with their default configs", cmdWorkers},
+	{"report", "Print a saved run report", cmdReport},
+	{"compare", "Compare two runs and detect regressions", cmdCompare},
+	{"replay", "Replay raw request logs into a run report", cmdReplay},
+	{"merge-hlog", "Merge HdrHistogram interval logs of several executors", cmdMergeHlog},
+	{"results", "List, tag, export and chart runs saved in the result store", cmdResults},
+	{"controller", "Start the controller managing executor groups", cmdController},
+}
 
-			// 使用 MarshalIndent 格式化 JSON
-			prettyJSONBytes, err := json.MarshalIndent(jsonData, "", "    ") // 4个空格的缩进
-			if err != nil {
-				logger.Error("Error formatting JSON: %v", err)
-				continue
-			}
-			prettyJSON = string(prettyJSONBytes)
+func usage() {
+	out := flag.CommandLine.Output()
+	_, _ = fmt.Fprintf(out, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(out, "  %-12s%s\n", c.name, c.desc)
+	}
+	_, _ = fmt.Fprintf(out, "\nRun '%s <command> -h' for help of a command.\n", os.Args[0])
+	_, _ = fmt.Fprintf(out, "Flags without a command (-D, -list_worker, ...) are still supported for compatibility.\n")
+}
 
-			// 记录工作名称和格式化后的 JSON
-			logger.Info("Worker: %s, with default config = %s", wN, prettyJSON)
-		}
-		os.Exit(0)
+// newFlagSet 创建子命令的参数集，-h 时打印子命令的说明
+func newFlagSet(name, args, desc string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\n%s\n\nFlags:\n", os.Args[0], name, args, desc)
+		fs.PrintDefaults()
 	}
-	// 检查配置
-	if err := checkConfig(); err != nil {
-		return err
+	return fs
+}
+
+// setupLog 按命令行参数配置日志，失败时返回false
+func setupLog() bool {
+	if err := logger.Configure(logOpts); err != nil {
+		logger.Error("Parse args err: %v", err)
+		return false
 	}
-	return nil
+	return true
 }
 
-// checkConfig 检查配置的有效性
-func checkConfig() error {
+// legacy 兼容没有子命令的旧参数：-D 启动执行器服务，-list_worker 打印工作器，否则本地压测
+func legacy(args []string) int {
+	cfg = defaultConfig()
+	fs := flag.CommandLine
+	addRunFlags(fs, &cfg)
+	addServeFlags(fs, &cfg)
+	fs.BoolVar(&cfg.GrpcCfg.Enable, "D", false, "Start with grpc server")
+	fs.BoolVar(&cfg.ListWorker, "list_worker", false, "Print supported workers")
+	addLogFlags(fs, &logOpts)
+	_ = fs.Parse(args)
+	if !setupLog() {
+		return exitUsage
+	}
+	applyTrace(fs, &cfg)
+	applyRequestLog(fs, &cfg)
+	if cfg.ListWorker {
+		return printWorkers()
+	}
 	if cfg.WorkerName == "" {
-		return fmt.Errorf("you must specify an executor worker name")
+		logger.Error("Parse args err: you must specify an executor worker name")
+		return exitUsage
 	}
 	if cfg.GrpcCfg.Enable {
-		if cfg.GrpcCfg.Port <= 0 {
-			return fmt.Errorf("invalid gRPC server port: %d", cfg.GrpcCfg.Port)
-		}
-		if cfg.GrpcCfg.RegistrationCtEndpoint == "" {
-			return fmt.Errorf("you must specify a remote controller endpoint with -R when using gRPC")
-		}
-		if cfg.GrpcCfg.LocalIp == "" {
-			return fmt.Errorf("you must specify a local IP address with -L when using gRPC")
-		}
-		if cfg.GrpcCfg.GroupName == "" {
-			return fmt.Errorf("you must specify a group name with -G when using gRPC")
-		}
-		if cfg.GrpcCfg.Name == "" {
-			return fmt.Errorf("you must specify an executor name with -N when using gRPC")
-		}
+		return serve()
 	}
-	return nil
+	if err := cfg.Check(); err != nil {
+		logger.Error("Parse args err: %v", err)
+		return exitUsage
+	}
+	return runLocal(nil)
 }
 
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU() + 2)
-	reg := utils.NewRegistrationUtils()
-	err := parseArg()
-	if err != nil {
-		logger.Fatal("Parse args err: %v", err)
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" && args[0] != "-help" {
+		os.Exit(legacy(args))
 	}
-	benchmarkRunner := runner.NewBenchRunner(cfg.Timeout * 1000 * 1000)
-	signal.Ignore(os.Interrupt, syscall.SIGINT)
-	ctx, cancel := context.WithCancel(context.Background())
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGALRM)
-	go func() {
-		for sig := range sigs {
-			pressCount++
-			if pressCount > 1 {
-				logger.Warning("recev signal %s, force to exit\n", sig)
-				cancel()
-				return
-			} else {
-				logger.Info("recev signal %s, waiting to exit, press Ctr + c force exit\n", sig)
-				benchmarkRunner.Stop()
-				err := reg.Unregister()
-				if err != nil {
-					logger.Error("Unregister with err: %v\n", err)
-				}
-				service.StopGrpcServer()
-			}
-		}
-	}()
-	if cfg.GrpcCfg.Enable {
-		w := worker.NewWorker(cfg.WorkerName)
-		cfg.WorkerConfig = w.DefaultConfig()
-		err = reg.Register(cfg)
-		if err != nil {
-			logger.Fatal("Can not connect to remote ctl %s with err: %v", cfg.GrpcCfg.RegistrationCtEndpoint, err)
-		}
-		err := service.StartGrpcServer(cfg.GrpcCfg.Port, benchmarkRunner)
-		if err != nil {
-			logger.Fatal("Start grpc failed with err: %v", err)
-		}
-	} else {
-		err := benchmarkRunner.Start(ctx, cfg)
-		if err != nil {
-			logger.Fatal("Run benchmark err: %v", err)
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" || name == "-help" {
+		usage()
+		os.Exit(exitOK)
+	}
+	for _, c := range commands {
+		if c.name == name {
+			os.Exit(c.run(args[1:]))
 		}
 	}
+	_, _ = fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(exitUsage)
 }
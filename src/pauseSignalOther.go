@@ -0,0 +1,8 @@
+//go:build !unix
+
+package main
+
+import "os"
+
+// pauseSignals 没有 SIGUSR1 的平台不支持通过信号暂停，可以在仪表盘中按 p 或通过 PausePerform 暂停
+var pauseSignals []os.Signal
@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// pauseSignals 切换暂停/恢复的信号
+var pauseSignals = []os.Signal{syscall.SIGUSR1}
@@ -19,10 +19,16 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PerformService_StartPerform_FullMethodName = "/perform.PerformService/StartPerform"
-	PerformService_StopPerform_FullMethodName  = "/perform.PerformService/StopPerform"
-	PerformService_CollectStats_FullMethodName = "/perform.PerformService/CollectStats"
-	PerformService_KeepAlive_FullMethodName    = "/perform.PerformService/KeepAlive"
+	PerformService_StartPerform_FullMethodName  = "/perform.PerformService/StartPerform"
+	PerformService_StopPerform_FullMethodName   = "/perform.PerformService/StopPerform"
+	PerformService_CollectStats_FullMethodName  = "/perform.PerformService/CollectStats"
+	PerformService_KeepAlive_FullMethodName     = "/perform.PerformService/KeepAlive"
+	PerformService_PausePerform_FullMethodName  = "/perform.PerformService/PausePerform"
+	PerformService_ResumePerform_FullMethodName = "/perform.PerformService/ResumePerform"
+	PerformService_StreamStats_FullMethodName   = "/perform.PerformService/StreamStats"
+	PerformService_GetRun_FullMethodName        = "/perform.PerformService/GetRun"
+	PerformService_ListRuns_FullMethodName      = "/perform.PerformService/ListRuns"
+	PerformService_SyncClock_FullMethodName     = "/perform.PerformService/SyncClock"
 )
 
 // PerformServiceClient is the client API for PerformService service.
@@ -33,6 +39,12 @@ type PerformServiceClient interface {
 	StopPerform(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*PerformMessage, error)
 	CollectStats(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*PerformMessage, error)
 	KeepAlive(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*ExecutorStatus, error)
+	PausePerform(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*CmRespMessage, error)
+	ResumePerform(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*CmRespMessage, error)
+	StreamStats(ctx context.Context, in *StatsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PerformMessage], error)
+	GetRun(ctx context.Context, in *RunQuery, opts ...grpc.CallOption) (*RunMessage, error)
+	ListRuns(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*RunListMessage, error)
+	SyncClock(ctx context.Context, in *ClockMessage, opts ...grpc.CallOption) (*ClockMessage, error)
 }
 
 type performServiceClient struct {
@@ -83,6 +95,75 @@ func (c *performServiceClient) KeepAlive(ctx context.Context, in *EmptyMessage,
 	return out, nil
 }
 
+func (c *performServiceClient) PausePerform(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*CmRespMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CmRespMessage)
+	err := c.cc.Invoke(ctx, PerformService_PausePerform_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *performServiceClient) ResumePerform(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*CmRespMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CmRespMessage)
+	err := c.cc.Invoke(ctx, PerformService_ResumePerform_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *performServiceClient) StreamStats(ctx context.Context, in *StatsStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PerformMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PerformService_ServiceDesc.Streams[0], PerformService_StreamStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StatsStreamRequest, PerformMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PerformService_StreamStatsClient = grpc.ServerStreamingClient[PerformMessage]
+
+func (c *performServiceClient) GetRun(ctx context.Context, in *RunQuery, opts ...grpc.CallOption) (*RunMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunMessage)
+	err := c.cc.Invoke(ctx, PerformService_GetRun_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *performServiceClient) ListRuns(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*RunListMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RunListMessage)
+	err := c.cc.Invoke(ctx, PerformService_ListRuns_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *performServiceClient) SyncClock(ctx context.Context, in *ClockMessage, opts ...grpc.CallOption) (*ClockMessage, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClockMessage)
+	err := c.cc.Invoke(ctx, PerformService_SyncClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PerformServiceServer is the server API for PerformService service.
 // All implementations must embed UnimplementedPerformServiceServer
 // for forward compatibility.
@@ -91,6 +172,12 @@ type PerformServiceServer interface {
 	StopPerform(context.Context, *EmptyMessage) (*PerformMessage, error)
 	CollectStats(context.Context, *EmptyMessage) (*PerformMessage, error)
 	KeepAlive(context.Context, *EmptyMessage) (*ExecutorStatus, error)
+	PausePerform(context.Context, *EmptyMessage) (*CmRespMessage, error)
+	ResumePerform(context.Context, *EmptyMessage) (*CmRespMessage, error)
+	StreamStats(*StatsStreamRequest, grpc.ServerStreamingServer[PerformMessage]) error
+	GetRun(context.Context, *RunQuery) (*RunMessage, error)
+	ListRuns(context.Context, *EmptyMessage) (*RunListMessage, error)
+	SyncClock(context.Context, *ClockMessage) (*ClockMessage, error)
 	mustEmbedUnimplementedPerformServiceServer()
 }
 
@@ -113,6 +200,24 @@ func (UnimplementedPerformServiceServer) CollectStats(context.Context, *EmptyMes
 func (UnimplementedPerformServiceServer) KeepAlive(context.Context, *EmptyMessage) (*ExecutorStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method KeepAlive not implemented")
 }
+func (UnimplementedPerformServiceServer) PausePerform(context.Context, *EmptyMessage) (*CmRespMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PausePerform not implemented")
+}
+func (UnimplementedPerformServiceServer) ResumePerform(context.Context, *EmptyMessage) (*CmRespMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumePerform not implemented")
+}
+func (UnimplementedPerformServiceServer) StreamStats(*StatsStreamRequest, grpc.ServerStreamingServer[PerformMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamStats not implemented")
+}
+func (UnimplementedPerformServiceServer) GetRun(context.Context, *RunQuery) (*RunMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRun not implemented")
+}
+func (UnimplementedPerformServiceServer) ListRuns(context.Context, *EmptyMessage) (*RunListMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRuns not implemented")
+}
+func (UnimplementedPerformServiceServer) SyncClock(context.Context, *ClockMessage) (*ClockMessage, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncClock not implemented")
+}
 func (UnimplementedPerformServiceServer) mustEmbedUnimplementedPerformServiceServer() {}
 func (UnimplementedPerformServiceServer) testEmbeddedByValue()                        {}
 
@@ -206,6 +311,107 @@ func _PerformService_KeepAlive_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PerformService_PausePerform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformServiceServer).PausePerform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformService_PausePerform_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformServiceServer).PausePerform(ctx, req.(*EmptyMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PerformService_ResumePerform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformServiceServer).ResumePerform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformService_ResumePerform_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformServiceServer).ResumePerform(ctx, req.(*EmptyMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PerformService_StreamStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StatsStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PerformServiceServer).StreamStats(m, &grpc.GenericServerStream[StatsStreamRequest, PerformMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PerformService_StreamStatsServer = grpc.ServerStreamingServer[PerformMessage]
+
+func _PerformService_GetRun_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RunQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformServiceServer).GetRun(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformService_GetRun_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformServiceServer).GetRun(ctx, req.(*RunQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PerformService_ListRuns_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformServiceServer).ListRuns(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformService_ListRuns_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformServiceServer).ListRuns(ctx, req.(*EmptyMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PerformService_SyncClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClockMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PerformServiceServer).SyncClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PerformService_SyncClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PerformServiceServer).SyncClock(ctx, req.(*ClockMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PerformService_ServiceDesc is the grpc.ServiceDesc for PerformService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -229,7 +435,33 @@ var PerformService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "KeepAlive",
 			Handler:    _PerformService_KeepAlive_Handler,
 		},
+		{
+			MethodName: "PausePerform",
+			Handler:    _PerformService_PausePerform_Handler,
+		},
+		{
+			MethodName: "ResumePerform",
+			Handler:    _PerformService_ResumePerform_Handler,
+		},
+		{
+			MethodName: "GetRun",
+			Handler:    _PerformService_GetRun_Handler,
+		},
+		{
+			MethodName: "ListRuns",
+			Handler:    _PerformService_ListRuns_Handler,
+		},
+		{
+			MethodName: "SyncClock",
+			Handler:    _PerformService_SyncClock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamStats",
+			Handler:       _PerformService_StreamStats_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "perform.proto",
 }
@@ -26,6 +26,7 @@ const (
 	Status_STATUS_RUNNING Status = 0 // 运行中
 	Status_STATUS_IDLE    Status = 1 // 空闲
 	Status_STATUS_ERROR   Status = 2 // 异常
+	Status_STATUS_PAUSED  Status = 3 // 暂停
 )
 
 // Enum value maps for Status.
@@ -34,11 +35,13 @@ var (
 		0: "STATUS_RUNNING",
 		1: "STATUS_IDLE",
 		2: "STATUS_ERROR",
+		3: "STATUS_PAUSED",
 	}
 	Status_value = map[string]int32{
 		"STATUS_RUNNING": 0,
 		"STATUS_IDLE":    1,
 		"STATUS_ERROR":   2,
+		"STATUS_PAUSED":  3,
 	}
 )
 
@@ -69,11 +72,74 @@ func (Status) EnumDescriptor() ([]byte, []int) {
 	return file_perform_proto_rawDescGZIP(), []int{0}
 }
 
+// CmRespMessage.code 的取值，兼容旧版本时非0即失败
+type ErrCode int32
+
+const (
+	ErrCode_ERR_OK             ErrCode = 0
+	ErrCode_ERR_INVALID_CONFIG ErrCode = 1 // 配置解析或校验失败
+	ErrCode_ERR_UNKNOWN_WORKER ErrCode = 2 // 执行器上没有该worker
+	ErrCode_ERR_RUN_ACTIVE     ErrCode = 3 // 已经有压测在进行
+	ErrCode_ERR_SETUP_FAILED   ErrCode = 4 // 全局前置失败
+	ErrCode_ERR_INVALID_STATE  ErrCode = 5 // 当前状态不支持该操作
+	ErrCode_ERR_INTERNAL       ErrCode = 6
+)
+
+// Enum value maps for ErrCode.
+var (
+	ErrCode_name = map[int32]string{
+		0: "ERR_OK",
+		1: "ERR_INVALID_CONFIG",
+		2: "ERR_UNKNOWN_WORKER",
+		3: "ERR_RUN_ACTIVE",
+		4: "ERR_SETUP_FAILED",
+		5: "ERR_INVALID_STATE",
+		6: "ERR_INTERNAL",
+	}
+	ErrCode_value = map[string]int32{
+		"ERR_OK":             0,
+		"ERR_INVALID_CONFIG": 1,
+		"ERR_UNKNOWN_WORKER": 2,
+		"ERR_RUN_ACTIVE":     3,
+		"ERR_SETUP_FAILED":   4,
+		"ERR_INVALID_STATE":  5,
+		"ERR_INTERNAL":       6,
+	}
+)
+
+func (x ErrCode) Enum() *ErrCode {
+	p := new(ErrCode)
+	*p = x
+	return p
+}
+
+func (x ErrCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_perform_proto_enumTypes[1].Descriptor()
+}
+
+func (ErrCode) Type() protoreflect.EnumType {
+	return &file_perform_proto_enumTypes[1]
+}
+
+func (x ErrCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrCode.Descriptor instead.
+func (ErrCode) EnumDescriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{1}
+}
+
 type StartMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Json          []byte                 `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Json           []byte                 `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	ControllerTime int64                  `protobuf:"varint,2,opt,name=controller_time,json=controllerTime,proto3" json:"controller_time,omitempty"` // 控制器发送请求时的时间(us)，用于计算执行器的时钟偏差
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *StartMessage) Reset() {
@@ -113,16 +179,70 @@ func (x *StartMessage) GetJson() []byte {
 	return nil
 }
 
+func (x *StartMessage) GetControllerTime() int64 {
+	if x != nil {
+		return x.ControllerTime
+	}
+	return 0
+}
+
+type ClockMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          int64                  `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"` // unix时间戳(us)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClockMessage) Reset() {
+	*x = ClockMessage{}
+	mi := &file_perform_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClockMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClockMessage) ProtoMessage() {}
+
+func (x *ClockMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClockMessage.ProtoReflect.Descriptor instead.
+func (*ClockMessage) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClockMessage) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
 type ExecutorStatus struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Status        Status                 `protobuf:"varint,1,opt,name=status,proto3,enum=perform.Status" json:"status,omitempty"` // 使用枚举类型表示状态
+	RunId         string                 `protobuf:"bytes,2,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`           // 最近一次压测的ID
+	State         string                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`                        // 最近一次压测的状态
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`                      // 失败原因
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ExecutorStatus) Reset() {
 	*x = ExecutorStatus{}
-	mi := &file_perform_proto_msgTypes[1]
+	mi := &file_perform_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -134,7 +254,7 @@ func (x *ExecutorStatus) String() string {
 func (*ExecutorStatus) ProtoMessage() {}
 
 func (x *ExecutorStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[1]
+	mi := &file_perform_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -147,7 +267,7 @@ func (x *ExecutorStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutorStatus.ProtoReflect.Descriptor instead.
 func (*ExecutorStatus) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{1}
+	return file_perform_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *ExecutorStatus) GetStatus() Status {
@@ -157,18 +277,40 @@ func (x *ExecutorStatus) GetStatus() Status {
 	return Status_STATUS_RUNNING
 }
 
+func (x *ExecutorStatus) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ExecutorStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ExecutorStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
 
 type CmRespMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
 	Message       []byte                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	RunId         string                 `protobuf:"bytes,3,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	ClockOffset   int64                  `protobuf:"varint,4,opt,name=clock_offset,json=clockOffset,proto3" json:"clock_offset,omitempty"` // 执行器收到请求的时间减去controller_time(us)，包含单程网络延迟
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CmRespMessage) Reset() {
 	*x = CmRespMessage{}
-	mi := &file_perform_proto_msgTypes[2]
+	mi := &file_perform_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -180,7 +322,7 @@ func (x *CmRespMessage) String() string {
 func (*CmRespMessage) ProtoMessage() {}
 
 func (x *CmRespMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[2]
+	mi := &file_perform_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -193,7 +335,7 @@ func (x *CmRespMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CmRespMessage.ProtoReflect.Descriptor instead.
 func (*CmRespMessage) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{2}
+	return file_perform_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CmRespMessage) GetCode() int32 {
@@ -210,6 +352,20 @@ func (x *CmRespMessage) GetMessage() []byte {
 	return nil
 }
 
+func (x *CmRespMessage) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *CmRespMessage) GetClockOffset() int64 {
+	if x != nil {
+		return x.ClockOffset
+	}
+	return 0
+}
+
 type EmptyMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -218,7 +374,7 @@ type EmptyMessage struct {
 
 func (x *EmptyMessage) Reset() {
 	*x = EmptyMessage{}
-	mi := &file_perform_proto_msgTypes[3]
+	mi := &file_perform_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -230,7 +386,7 @@ func (x *EmptyMessage) String() string {
 func (*EmptyMessage) ProtoMessage() {}
 
 func (x *EmptyMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[3]
+	mi := &file_perform_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -243,7 +399,51 @@ func (x *EmptyMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyMessage.ProtoReflect.Descriptor instead.
 func (*EmptyMessage) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{3}
+	return file_perform_proto_rawDescGZIP(), []int{4}
+}
+
+type StatsStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Interval      int64                  `protobuf:"varint,1,opt,name=interval,proto3" json:"interval,omitempty"` // 推送周期(s)，会按执行器的统计周期取整，<=0时每个统计周期推送一次
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatsStreamRequest) Reset() {
+	*x = StatsStreamRequest{}
+	mi := &file_perform_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsStreamRequest) ProtoMessage() {}
+
+func (x *StatsStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatsStreamRequest.ProtoReflect.Descriptor instead.
+func (*StatsStreamRequest) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StatsStreamRequest) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
 }
 
 type PerformMessage struct {
@@ -256,7 +456,7 @@ type PerformMessage struct {
 
 func (x *PerformMessage) Reset() {
 	*x = PerformMessage{}
-	mi := &file_perform_proto_msgTypes[4]
+	mi := &file_perform_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -268,7 +468,7 @@ func (x *PerformMessage) String() string {
 func (*PerformMessage) ProtoMessage() {}
 
 func (x *PerformMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[4]
+	mi := &file_perform_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -281,7 +481,7 @@ func (x *PerformMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PerformMessage.ProtoReflect.Descriptor instead.
 func (*PerformMessage) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{4}
+	return file_perform_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *PerformMessage) GetCode() int32 {
@@ -298,23 +498,32 @@ func (x *PerformMessage) GetStats() *PerformStats {
 	return nil
 }
 
-
 type PerformStats struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ErrCount      int64                  `protobuf:"varint,1,opt,name=err_count,json=errCount,proto3" json:"err_count,omitempty"`
-	SendCount     int64                  `protobuf:"varint,2,opt,name=send_count,json=sendCount,proto3" json:"send_count,omitempty"`
-	SendBytes     int64                  `protobuf:"varint,3,opt,name=send_bytes,json=sendBytes,proto3" json:"send_bytes,omitempty"`
-	RecvBytes     int64                  `protobuf:"varint,4,opt,name=recv_bytes,json=recvBytes,proto3" json:"recv_bytes,omitempty"`
-	Duration      int64                  `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
-	Latency       []*Record              `protobuf:"bytes,6,rep,name=latency,proto3" json:"latency,omitempty"`
-	ErrMsgs       [][]byte               `protobuf:"bytes,7,rep,name=err_msgs,json=errMsgs,proto3" json:"err_msgs,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrCount       int64                  `protobuf:"varint,1,opt,name=err_count,json=errCount,proto3" json:"err_count,omitempty"`
+	SendCount      int64                  `protobuf:"varint,2,opt,name=send_count,json=sendCount,proto3" json:"send_count,omitempty"`
+	SendBytes      int64                  `protobuf:"varint,3,opt,name=send_bytes,json=sendBytes,proto3" json:"send_bytes,omitempty"`
+	RecvBytes      int64                  `protobuf:"varint,4,opt,name=recv_bytes,json=recvBytes,proto3" json:"recv_bytes,omitempty"`
+	Duration       int64                  `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	Latency        []*Record              `protobuf:"bytes,6,rep,name=latency,proto3" json:"latency,omitempty"`
+	ErrMsgs        [][]byte               `protobuf:"bytes,7,rep,name=err_msgs,json=errMsgs,proto3" json:"err_msgs,omitempty"`
+	PausedDuration int64                  `protobuf:"varint,8,opt,name=paused_duration,json=pausedDuration,proto3" json:"paused_duration,omitempty"` // 区间内暂停的时长(us)
+	Paused         bool                   `protobuf:"varint,9,opt,name=paused,proto3" json:"paused,omitempty"`                                       // 是否处于暂停状态
+	Histogram      []byte                 `protobuf:"bytes,10,opt,name=histogram,proto3" json:"histogram,omitempty"`                                 // 区间时延直方图，HdrHistogram V2 compressed 编码，跨执行器合并请使用该字段
+	HistLowest     int64                  `protobuf:"varint,11,opt,name=hist_lowest,json=histLowest,proto3" json:"hist_lowest,omitempty"`            // 直方图可记录的最小值(us)
+	HistHighest    int64                  `protobuf:"varint,12,opt,name=hist_highest,json=histHighest,proto3" json:"hist_highest,omitempty"`         // 直方图可记录的最大值(us)
+	HistSigFigs    int32                  `protobuf:"varint,13,opt,name=hist_sig_figs,json=histSigFigs,proto3" json:"hist_sig_figs,omitempty"`       // 直方图的有效位数
+	Percentiles    []*Percentile          `protobuf:"bytes,14,rep,name=percentiles,proto3" json:"percentiles,omitempty"`                             // 按压测配置的分位数计算的区间时延(us)，100 表示最大值
+	Generator      *GeneratorStats        `protobuf:"bytes,15,opt,name=generator,proto3" json:"generator,omitempty"`                                 // 执行器自身的资源使用
+	Metrics        []*Metric              `protobuf:"bytes,16,rep,name=metrics,proto3" json:"metrics,omitempty"`                                     // 工作器记录的自定义指标
+	Phases         []*Phase               `protobuf:"bytes,17,rep,name=phases,proto3" json:"phases,omitempty"`                                       // 请求各阶段的耗时
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *PerformStats) Reset() {
 	*x = PerformStats{}
-	mi := &file_perform_proto_msgTypes[5]
+	mi := &file_perform_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -326,7 +535,7 @@ func (x *PerformStats) String() string {
 func (*PerformStats) ProtoMessage() {}
 
 func (x *PerformStats) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[5]
+	mi := &file_perform_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -339,7 +548,7 @@ func (x *PerformStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PerformStats.ProtoReflect.Descriptor instead.
 func (*PerformStats) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{5}
+	return file_perform_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PerformStats) GetErrCount() int64 {
@@ -391,29 +600,100 @@ func (x *PerformStats) GetErrMsgs() [][]byte {
 	return nil
 }
 
-type Record struct {
+func (x *PerformStats) GetPausedDuration() int64 {
+	if x != nil {
+		return x.PausedDuration
+	}
+	return 0
+}
+
+func (x *PerformStats) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *PerformStats) GetHistogram() []byte {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+func (x *PerformStats) GetHistLowest() int64 {
+	if x != nil {
+		return x.HistLowest
+	}
+	return 0
+}
+
+func (x *PerformStats) GetHistHighest() int64 {
+	if x != nil {
+		return x.HistHighest
+	}
+	return 0
+}
+
+func (x *PerformStats) GetHistSigFigs() int32 {
+	if x != nil {
+		return x.HistSigFigs
+	}
+	return 0
+}
+
+func (x *PerformStats) GetPercentiles() []*Percentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+func (x *PerformStats) GetGenerator() *GeneratorStats {
+	if x != nil {
+		return x.Generator
+	}
+	return nil
+}
+
+func (x *PerformStats) GetMetrics() []*Metric {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *PerformStats) GetPhases() []*Phase {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+// Phase 区间内一个阶段（dns、connect、tls、ttfb、transfer 等）的耗时
+type Phase struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           int64                  `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
-	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Histogram     []byte                 `protobuf:"bytes,2,opt,name=histogram,proto3" json:"histogram,omitempty"` // 耗时直方图(us)，HdrHistogram V2 compressed 编码
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Record) Reset() {
-	*x = Record{}
-	mi := &file_perform_proto_msgTypes[6]
+func (x *Phase) Reset() {
+	*x = Phase{}
+	mi := &file_perform_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Record) String() string {
+func (x *Phase) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Record) ProtoMessage() {}
+func (*Phase) ProtoMessage() {}
 
-func (x *Record) ProtoReflect() protoreflect.Message {
-	mi := &file_perform_proto_msgTypes[6]
+func (x *Phase) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -424,133 +704,1233 @@ func (x *Record) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Record.ProtoReflect.Descriptor instead.
-func (*Record) Descriptor() ([]byte, []int) {
-	return file_perform_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use Phase.ProtoReflect.Descriptor instead.
+func (*Phase) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Record) GetKey() int64 {
+func (x *Phase) GetName() string {
 	if x != nil {
-		return x.Key
+		return x.Name
 	}
-	return 0
+	return ""
 }
 
-func (x *Record) GetValue() int64 {
+func (x *Phase) GetHistogram() []byte {
 	if x != nil {
-		return x.Value
+		return x.Histogram
 	}
-	return 0
+	return nil
 }
 
-var File_perform_proto protoreflect.FileDescriptor
-
-var file_perform_proto_rawDesc = []byte{
-	0x0a, 0x0d, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x22, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x72,
-	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x22, 0x39, 0x0a, 0x0e,
-	0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x27,
-	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f,
-	0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x3d, 0x0a, 0x0d, 0x43, 0x6d, 0x52, 0x65, 0x73,
-	0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x6d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x0e, 0x0a, 0x0c, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0x51, 0x0a, 0x0e, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72,
-	0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x2b, 0x0a, 0x05,
-	0x73, 0x74, 0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x65,
-	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61,
-	0x74, 0x73, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x22, 0xea, 0x01, 0x0a, 0x0c, 0x50, 0x65,
-	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x72,
-	0x72, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65,
-	0x72, 0x72, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6e, 0x64, 0x5f,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x6e,
-	0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x62,
-	0x79, 0x74, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x64,
-	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x62, 0x79,
-	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x63, 0x76, 0x42,
-	0x79, 0x74, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x29, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28,
-	0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x63, 0x6f,
-	0x72, 0x64, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x65,
-	0x72, 0x72, 0x5f, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x65,
-	0x72, 0x72, 0x4d, 0x73, 0x67, 0x73, 0x22, 0x30, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6b,
-	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x2a, 0x3f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52, 0x55, 0x4e,
-	0x4e, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
-	0x5f, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x54, 0x41, 0x54, 0x55,
-	0x53, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x32, 0x8b, 0x02, 0x0a, 0x0e, 0x50, 0x65,
-	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x0c,
-	0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e, 0x70,
-	0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x43, 0x6d,
-	0x52, 0x65, 0x73, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0b, 0x53,
-	0x74, 0x6f, 0x70, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72,
-	0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x66,
-	0x6f, 0x72, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x43, 0x6f,
-	0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72,
-	0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
-	0x65, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x66,
-	0x6f, 0x72, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x4b, 0x65,
-	0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72,
-	0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a, 0x17,
-	0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x6f,
-	0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x42, 0x13, 0x5a, 0x11, 0x73, 0x72, 0x63, 0x2f, 0x70,
-	0x62, 0x3b, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+// Metric 区间内的一个自定义指标
+type Metric struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`           // counter, gauge, histogram
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"`       // 计数为区间内的增量，仪表为最后一次记录的值，直方图为记录的个数
+	Histogram     []byte                 `protobuf:"bytes,4,opt,name=histogram,proto3" json:"histogram,omitempty"` // 直方图，HdrHistogram V2 compressed 编码
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var (
-	file_perform_proto_rawDescOnce sync.Once
-	file_perform_proto_rawDescData = file_perform_proto_rawDesc
-)
+func (x *Metric) Reset() {
+	*x = Metric{}
+	mi := &file_perform_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func file_perform_proto_rawDescGZIP() []byte {
-	file_perform_proto_rawDescOnce.Do(func() {
-		file_perform_proto_rawDescData = protoimpl.X.CompressGZIP(file_perform_proto_rawDescData)
-	})
-	return file_perform_proto_rawDescData
+func (x *Metric) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_perform_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_perform_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_perform_proto_goTypes = []any{
-	(Status)(0),            // 0: perform.Status
-	(*StartMessage)(nil),   // 1: perform.StartMessage
-	(*ExecutorStatus)(nil), // 2: perform.ExecutorStatus
-	(*CmRespMessage)(nil),  // 3: perform.CmRespMessage
-	(*EmptyMessage)(nil),   // 4: perform.EmptyMessage
-	(*PerformMessage)(nil), // 5: perform.PerformMessage
-	(*PerformStats)(nil),   // 6: perform.PerformStats
-	(*Record)(nil),         // 7: perform.Record
+func (*Metric) ProtoMessage() {}
+
+func (x *Metric) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_perform_proto_depIdxs = []int32{
-	0, // 0: perform.ExecutorStatus.status:type_name -> perform.Status
-	6, // 1: perform.PerformMessage.stats:type_name -> perform.PerformStats
-	7, // 2: perform.PerformStats.latency:type_name -> perform.Record
-	1, // 3: perform.PerformService.StartPerform:input_type -> perform.StartMessage
-	4, // 4: perform.PerformService.StopPerform:input_type -> perform.EmptyMessage
-	4, // 5: perform.PerformService.CollectStats:input_type -> perform.EmptyMessage
-	4, // 6: perform.PerformService.KeepAlive:input_type -> perform.EmptyMessage
-	3, // 7: perform.PerformService.StartPerform:output_type -> perform.CmRespMessage
-	5, // 8: perform.PerformService.StopPerform:output_type -> perform.PerformMessage
-	5, // 9: perform.PerformService.CollectStats:output_type -> perform.PerformMessage
-	2, // 10: perform.PerformService.KeepAlive:output_type -> perform.ExecutorStatus
-	7, // [7:11] is the sub-list for method output_type
-	3, // [3:7] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+
+// Deprecated: Use Metric.ProtoReflect.Descriptor instead.
+func (*Metric) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{9}
 }
 
-func init() { file_perform_proto_init() }
+func (x *Metric) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Metric) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Metric) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *Metric) GetHistogram() []byte {
+	if x != nil {
+		return x.Histogram
+	}
+	return nil
+}
+
+// GeneratorStats 执行器自身在区间内的资源使用，用于判断瓶颈是被测服务还是执行器
+type GeneratorStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Cpu             float64                `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"`                                                 // 进程CPU使用率(%)，100 表示占满一个核
+	Cores           int64                  `protobuf:"varint,2,opt,name=cores,proto3" json:"cores,omitempty"`                                              // CPU核数
+	Goroutines      int64                  `protobuf:"varint,3,opt,name=goroutines,proto3" json:"goroutines,omitempty"`                                    // 协程数
+	GcCount         int64                  `protobuf:"varint,4,opt,name=gc_count,json=gcCount,proto3" json:"gc_count,omitempty"`                           // 区间内GC次数
+	GcPause         int64                  `protobuf:"varint,5,opt,name=gc_pause,json=gcPause,proto3" json:"gc_pause,omitempty"`                           // 区间内GC暂停总时长(us)
+	GcPauseMax      int64                  `protobuf:"varint,6,opt,name=gc_pause_max,json=gcPauseMax,proto3" json:"gc_pause_max,omitempty"`                // 区间内最长的GC暂停(us)
+	SchedLatencyP99 int64                  `protobuf:"varint,7,opt,name=sched_latency_p99,json=schedLatencyP99,proto3" json:"sched_latency_p99,omitempty"` // 调度延迟p99(us)
+	SchedLatencyMax int64                  `protobuf:"varint,8,opt,name=sched_latency_max,json=schedLatencyMax,proto3" json:"sched_latency_max,omitempty"` // 调度延迟最大值(us)
+	TargetRate      int64                  `protobuf:"varint,9,opt,name=target_rate,json=targetRate,proto3" json:"target_rate,omitempty"`                  // 目标速率，0 表示不限速
+	LimiterLag      int64                  `protobuf:"varint,10,opt,name=limiter_lag,json=limiterLag,proto3" json:"limiter_lag,omitempty"`                 // 区间内实际发压落后于目标速率的时长(us)
+	Saturated       bool                   `protobuf:"varint,11,opt,name=saturated,proto3" json:"saturated,omitempty"`                                     // 执行器跟不上目标速率
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GeneratorStats) Reset() {
+	*x = GeneratorStats{}
+	mi := &file_perform_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeneratorStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeneratorStats) ProtoMessage() {}
+
+func (x *GeneratorStats) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeneratorStats.ProtoReflect.Descriptor instead.
+func (*GeneratorStats) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GeneratorStats) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetCores() int64 {
+	if x != nil {
+		return x.Cores
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetGoroutines() int64 {
+	if x != nil {
+		return x.Goroutines
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetGcCount() int64 {
+	if x != nil {
+		return x.GcCount
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetGcPause() int64 {
+	if x != nil {
+		return x.GcPause
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetGcPauseMax() int64 {
+	if x != nil {
+		return x.GcPauseMax
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetSchedLatencyP99() int64 {
+	if x != nil {
+		return x.SchedLatencyP99
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetSchedLatencyMax() int64 {
+	if x != nil {
+		return x.SchedLatencyMax
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetTargetRate() int64 {
+	if x != nil {
+		return x.TargetRate
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetLimiterLag() int64 {
+	if x != nil {
+		return x.LimiterLag
+	}
+	return 0
+}
+
+func (x *GeneratorStats) GetSaturated() bool {
+	if x != nil {
+		return x.Saturated
+	}
+	return false
+}
+
+type Record struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           int64                  `protobuf:"varint,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_perform_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Record) GetKey() int64 {
+	if x != nil {
+		return x.Key
+	}
+	return 0
+}
+
+func (x *Record) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type RunQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"` // 为空时查询最近一次压测
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunQuery) Reset() {
+	*x = RunQuery{}
+	mi := &file_perform_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunQuery) ProtoMessage() {}
+
+func (x *RunQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunQuery.ProtoReflect.Descriptor instead.
+func (*RunQuery) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RunQuery) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+type Percentile struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Percentile    float64                `protobuf:"fixed64,1,opt,name=percentile,proto3" json:"percentile,omitempty"`
+	Value         int64                  `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Percentile) Reset() {
+	*x = Percentile{}
+	mi := &file_perform_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Percentile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Percentile) ProtoMessage() {}
+
+func (x *Percentile) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Percentile.ProtoReflect.Descriptor instead.
+func (*Percentile) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Percentile) GetPercentile() float64 {
+	if x != nil {
+		return x.Percentile
+	}
+	return 0
+}
+
+func (x *Percentile) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type RunSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Complete       int64                  `protobuf:"varint,1,opt,name=complete,proto3" json:"complete,omitempty"`
+	Duration       int64                  `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"` // 实际发压时长(us)，不含暂停
+	ReqPerSec      int64                  `protobuf:"varint,3,opt,name=req_per_sec,json=reqPerSec,proto3" json:"req_per_sec,omitempty"`
+	ErrCount       int64                  `protobuf:"varint,4,opt,name=err_count,json=errCount,proto3" json:"err_count,omitempty"`
+	Min            int64                  `protobuf:"varint,5,opt,name=min,proto3" json:"min,omitempty"`
+	Max            int64                  `protobuf:"varint,6,opt,name=max,proto3" json:"max,omitempty"`
+	Mean           float64                `protobuf:"fixed64,7,opt,name=mean,proto3" json:"mean,omitempty"`
+	StdDev         float64                `protobuf:"fixed64,8,opt,name=std_dev,json=stdDev,proto3" json:"std_dev,omitempty"`
+	Percentiles    []*Percentile          `protobuf:"bytes,9,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	DrainInFlight  int64                  `protobuf:"varint,10,opt,name=drain_in_flight,json=drainInFlight,proto3" json:"drain_in_flight,omitempty"`
+	DrainCompleted int64                  `protobuf:"varint,11,opt,name=drain_completed,json=drainCompleted,proto3" json:"drain_completed,omitempty"`
+	DrainAborted   int64                  `protobuf:"varint,12,opt,name=drain_aborted,json=drainAborted,proto3" json:"drain_aborted,omitempty"`
+	Metrics        []*MetricSummary       `protobuf:"bytes,13,rep,name=metrics,proto3" json:"metrics,omitempty"` // 自定义指标的汇总
+	Phases         []*PhaseSummary        `protobuf:"bytes,14,rep,name=phases,proto3" json:"phases,omitempty"`   // 请求各阶段耗时的汇总
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RunSummary) Reset() {
+	*x = RunSummary{}
+	mi := &file_perform_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunSummary) ProtoMessage() {}
+
+func (x *RunSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunSummary.ProtoReflect.Descriptor instead.
+func (*RunSummary) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RunSummary) GetComplete() int64 {
+	if x != nil {
+		return x.Complete
+	}
+	return 0
+}
+
+func (x *RunSummary) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *RunSummary) GetReqPerSec() int64 {
+	if x != nil {
+		return x.ReqPerSec
+	}
+	return 0
+}
+
+func (x *RunSummary) GetErrCount() int64 {
+	if x != nil {
+		return x.ErrCount
+	}
+	return 0
+}
+
+func (x *RunSummary) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *RunSummary) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *RunSummary) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *RunSummary) GetStdDev() float64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *RunSummary) GetPercentiles() []*Percentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+func (x *RunSummary) GetDrainInFlight() int64 {
+	if x != nil {
+		return x.DrainInFlight
+	}
+	return 0
+}
+
+func (x *RunSummary) GetDrainCompleted() int64 {
+	if x != nil {
+		return x.DrainCompleted
+	}
+	return 0
+}
+
+func (x *RunSummary) GetDrainAborted() int64 {
+	if x != nil {
+		return x.DrainAborted
+	}
+	return 0
+}
+
+func (x *RunSummary) GetMetrics() []*MetricSummary {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *RunSummary) GetPhases() []*PhaseSummary {
+	if x != nil {
+		return x.Phases
+	}
+	return nil
+}
+
+// PhaseSummary 整个压测一个阶段耗时的汇总(us)
+type PhaseSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Min           int64                  `protobuf:"varint,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int64                  `protobuf:"varint,4,opt,name=max,proto3" json:"max,omitempty"`
+	Mean          float64                `protobuf:"fixed64,5,opt,name=mean,proto3" json:"mean,omitempty"`
+	Percentiles   []*Percentile          `protobuf:"bytes,6,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PhaseSummary) Reset() {
+	*x = PhaseSummary{}
+	mi := &file_perform_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PhaseSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PhaseSummary) ProtoMessage() {}
+
+func (x *PhaseSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PhaseSummary.ProtoReflect.Descriptor instead.
+func (*PhaseSummary) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PhaseSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *PhaseSummary) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PhaseSummary) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PhaseSummary) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *PhaseSummary) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *PhaseSummary) GetPercentiles() []*Percentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+// MetricSummary 整个压测的自定义指标汇总，min、max、mean、percentiles 只有直方图有
+type MetricSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`     // counter, gauge, histogram
+	Value         float64                `protobuf:"fixed64,3,opt,name=value,proto3" json:"value,omitempty"` // 计数为总数，仪表为最后一次记录的值，直方图为记录的个数
+	Min           int64                  `protobuf:"varint,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max           int64                  `protobuf:"varint,5,opt,name=max,proto3" json:"max,omitempty"`
+	Mean          float64                `protobuf:"fixed64,6,opt,name=mean,proto3" json:"mean,omitempty"`
+	Percentiles   []*Percentile          `protobuf:"bytes,7,rep,name=percentiles,proto3" json:"percentiles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSummary) Reset() {
+	*x = MetricSummary{}
+	mi := &file_perform_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSummary) ProtoMessage() {}
+
+func (x *MetricSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricSummary.ProtoReflect.Descriptor instead.
+func (*MetricSummary) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MetricSummary) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *MetricSummary) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *MetricSummary) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMin() int64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMax() int64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *MetricSummary) GetPercentiles() []*Percentile {
+	if x != nil {
+		return x.Percentiles
+	}
+	return nil
+}
+
+type RunInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	State         string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"` // starting, setup-failed, running, draining, finished, failed
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	StartTime     int64                  `protobuf:"varint,4,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // us
+	EndTime       int64                  `protobuf:"varint,5,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // us
+	Config        []byte                 `protobuf:"bytes,6,opt,name=config,proto3" json:"config,omitempty"`                         // BenchConfig json
+	Summary       *RunSummary            `protobuf:"bytes,7,opt,name=summary,proto3" json:"summary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunInfo) Reset() {
+	*x = RunInfo{}
+	mi := &file_perform_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunInfo) ProtoMessage() {}
+
+func (x *RunInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunInfo.ProtoReflect.Descriptor instead.
+func (*RunInfo) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RunInfo) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *RunInfo) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *RunInfo) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RunInfo) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *RunInfo) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *RunInfo) GetConfig() []byte {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *RunInfo) GetSummary() *RunSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+type RunMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message       []byte                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Run           *RunInfo               `protobuf:"bytes,3,opt,name=run,proto3" json:"run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunMessage) Reset() {
+	*x = RunMessage{}
+	mi := &file_perform_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunMessage) ProtoMessage() {}
+
+func (x *RunMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunMessage.ProtoReflect.Descriptor instead.
+func (*RunMessage) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RunMessage) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RunMessage) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *RunMessage) GetRun() *RunInfo {
+	if x != nil {
+		return x.Run
+	}
+	return nil
+}
+
+type RunListMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Runs          []*RunInfo             `protobuf:"bytes,2,rep,name=runs,proto3" json:"runs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunListMessage) Reset() {
+	*x = RunListMessage{}
+	mi := &file_perform_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunListMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunListMessage) ProtoMessage() {}
+
+func (x *RunListMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_perform_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunListMessage.ProtoReflect.Descriptor instead.
+func (*RunListMessage) Descriptor() ([]byte, []int) {
+	return file_perform_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RunListMessage) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *RunListMessage) GetRuns() []*RunInfo {
+	if x != nil {
+		return x.Runs
+	}
+	return nil
+}
+
+var File_perform_proto protoreflect.FileDescriptor
+
+var file_perform_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x07, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x22, 0x4b, 0x0a, 0x0c, 0x53, 0x74, 0x61, 0x72,
+	0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6a, 0x73, 0x6f, 0x6e,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x6a, 0x73, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f,
+	0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x6c, 0x65,
+	0x72, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x22, 0x0a, 0x0c, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x6d, 0x65, 0x22, 0x7e, 0x0a, 0x0e, 0x45, 0x78, 0x65,
+	0x63, 0x75, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x27, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0f, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73,
+	0x74, 0x61, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x0d, 0x43, 0x6d, 0x52,
+	0x65, 0x73, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f,
+	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x63, 0x6c, 0x6f, 0x63, 0x6b, 0x4f, 0x66, 0x66, 0x73,
+	0x65, 0x74, 0x22, 0x0e, 0x0a, 0x0c, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x22, 0x30, 0x0a, 0x12, 0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x22, 0x51, 0x0a, 0x0e, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x2b, 0x0a, 0x05, 0x73, 0x74,
+	0x61, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66,
+	0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x22, 0xf2, 0x04, 0x0a, 0x0c, 0x50, 0x65, 0x72, 0x66,
+	0x6f, 0x72, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x72, 0x72,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x63, 0x6f,
+	0x75, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x62, 0x79, 0x74, 0x65,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72, 0x65, 0x63, 0x76, 0x42, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29,
+	0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x19, 0x0a, 0x08, 0x65, 0x72, 0x72,
+	0x5f, 0x6d, 0x73, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x07, 0x65, 0x72, 0x72,
+	0x4d, 0x73, 0x67, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x64,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a,
+	0x06, 0x70, 0x61, 0x75, 0x73, 0x65, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x70,
+	0x61, 0x75, 0x73, 0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72,
+	0x61, 0x6d, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67,
+	0x72, 0x61, 0x6d, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x69, 0x73, 0x74, 0x5f, 0x6c, 0x6f, 0x77, 0x65,
+	0x73, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x68, 0x69, 0x73, 0x74, 0x4c, 0x6f,
+	0x77, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x68, 0x69, 0x73, 0x74, 0x5f, 0x68, 0x69, 0x67,
+	0x68, 0x65, 0x73, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x68, 0x69, 0x73, 0x74,
+	0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x68, 0x69, 0x73, 0x74, 0x5f,
+	0x73, 0x69, 0x67, 0x5f, 0x66, 0x69, 0x67, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x68, 0x69, 0x73, 0x74, 0x53, 0x69, 0x67, 0x46, 0x69, 0x67, 0x73, 0x12, 0x35, 0x0a, 0x0b, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x69, 0x6c, 0x65, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c,
+	0x65, 0x73, 0x12, 0x35, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e,
+	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x09,
+	0x67, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12, 0x29, 0x0a, 0x07, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x70, 0x65, 0x72,
+	0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x07, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x18, 0x11,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50,
+	0x68, 0x61, 0x73, 0x65, 0x52, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x22, 0x39, 0x0a, 0x05,
+	0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x69, 0x73,
+	0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x68, 0x69,
+	0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x22, 0x64, 0x0a, 0x06, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x09, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x67, 0x72, 0x61, 0x6d, 0x22, 0xe8, 0x02,
+	0x0a, 0x0e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x73,
+	0x12, 0x10, 0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x63,
+	0x70, 0x75, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x05, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x67, 0x6f, 0x72, 0x6f,
+	0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x67, 0x6f,
+	0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x63, 0x5f, 0x63,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x67, 0x63, 0x43, 0x6f,
+	0x75, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x63, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x67, 0x63, 0x50, 0x61, 0x75, 0x73, 0x65, 0x12, 0x20,
+	0x0a, 0x0c, 0x67, 0x63, 0x5f, 0x70, 0x61, 0x75, 0x73, 0x65, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x67, 0x63, 0x50, 0x61, 0x75, 0x73, 0x65, 0x4d, 0x61, 0x78,
+	0x12, 0x2a, 0x0a, 0x11, 0x73, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63,
+	0x79, 0x5f, 0x70, 0x39, 0x39, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x4c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x50, 0x39, 0x39, 0x12, 0x2a, 0x0a, 0x11,
+	0x73, 0x63, 0x68, 0x65, 0x64, 0x5f, 0x6c, 0x61, 0x74, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x6d, 0x61,
+	0x78, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x73, 0x63, 0x68, 0x65, 0x64, 0x4c, 0x61,
+	0x74, 0x65, 0x6e, 0x63, 0x79, 0x4d, 0x61, 0x78, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x61, 0x72, 0x67,
+	0x65, 0x74, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x74,
+	0x61, 0x72, 0x67, 0x65, 0x74, 0x52, 0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x65, 0x72, 0x5f, 0x6c, 0x61, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a,
+	0x6c, 0x69, 0x6d, 0x69, 0x74, 0x65, 0x72, 0x4c, 0x61, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x61,
+	0x74, 0x75, 0x72, 0x61, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x73,
+	0x61, 0x74, 0x75, 0x72, 0x61, 0x74, 0x65, 0x64, 0x22, 0x30, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x22, 0x21, 0x0a, 0x08, 0x52, 0x75,
+	0x6e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64, 0x22, 0x42, 0x0a,
+	0x0a, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x0a, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x22, 0xe0, 0x03, 0x0a, 0x0a, 0x52, 0x75, 0x6e, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x1a, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x5f,
+	0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x72,
+	0x65, 0x71, 0x50, 0x65, 0x72, 0x53, 0x65, 0x63, 0x12, 0x1b, 0x0a, 0x09, 0x65, 0x72, 0x72, 0x5f,
+	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x65, 0x72, 0x72,
+	0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x61, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61,
+	0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x17, 0x0a,
+	0x07, 0x73, 0x74, 0x64, 0x5f, 0x64, 0x65, 0x76, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06,
+	0x73, 0x74, 0x64, 0x44, 0x65, 0x76, 0x12, 0x35, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65,
+	0x52, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x12, 0x26, 0x0a,
+	0x0f, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x5f, 0x69, 0x6e, 0x5f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x49, 0x6e, 0x46,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x5f, 0x63,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e,
+	0x64, 0x72, 0x61, 0x69, 0x6e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x23,
+	0x0a, 0x0d, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x5f, 0x61, 0x62, 0x6f, 0x72, 0x74, 0x65, 0x64, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x64, 0x72, 0x61, 0x69, 0x6e, 0x41, 0x62, 0x6f, 0x72,
+	0x74, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x0d,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x4d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x6d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x70, 0x68, 0x61, 0x73, 0x65, 0x73, 0x18,
+	0x0e, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e,
+	0x50, 0x68, 0x61, 0x73, 0x65, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x06, 0x70, 0x68,
+	0x61, 0x73, 0x65, 0x73, 0x22, 0xa7, 0x01, 0x0a, 0x0c, 0x50, 0x68, 0x61, 0x73, 0x65, 0x53, 0x75,
+	0x6d, 0x6d, 0x61, 0x72, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x69,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x6d, 0x61, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x35, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65,
+	0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70,
+	0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c,
+	0x65, 0x52, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x22, 0xbc,
+	0x01, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x53, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d, 0x69, 0x6e,
+	0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x6d,
+	0x61, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12, 0x35, 0x0a, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e,
+	0x74, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65,
+	0x52, 0x0b, 0x70, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x69, 0x6c, 0x65, 0x73, 0x22, 0xcf, 0x01,
+	0x0a, 0x07, 0x52, 0x75, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x15, 0x0a, 0x06, 0x72, 0x75, 0x6e,
+	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x75, 0x6e, 0x49, 0x64,
+	0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1d,
+	0x0a, 0x0a, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x19, 0x0a,
+	0x08, 0x65, 0x6e, 0x64, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x65, 0x6e, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x2d, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x75, 0x6e, 0x53,
+	0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79, 0x22,
+	0x5e, 0x0a, 0x0a, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x22, 0x0a, 0x03, 0x72,
+	0x75, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f,
+	0x72, 0x6d, 0x2e, 0x52, 0x75, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x72, 0x75, 0x6e, 0x22,
+	0x4a, 0x0a, 0x0e, 0x52, 0x75, 0x6e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x75,
+	0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x72, 0x75, 0x6e, 0x73, 0x2a, 0x52, 0x0a, 0x06, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x0e, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x52, 0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x49, 0x44, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x02, 0x12, 0x11, 0x0a, 0x0d,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x50, 0x41, 0x55, 0x53, 0x45, 0x44, 0x10, 0x03, 0x2a,
+	0x98, 0x01, 0x0a, 0x07, 0x45, 0x72, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x45,
+	0x52, 0x52, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x52, 0x52, 0x5f, 0x49,
+	0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x01, 0x12,
+	0x16, 0x0a, 0x12, 0x45, 0x52, 0x52, 0x5f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x57,
+	0x4f, 0x52, 0x4b, 0x45, 0x52, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x52, 0x52, 0x5f, 0x52,
+	0x55, 0x4e, 0x5f, 0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x45,
+	0x52, 0x52, 0x5f, 0x53, 0x45, 0x54, 0x55, 0x50, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x04, 0x12, 0x15, 0x0a, 0x11, 0x45, 0x52, 0x52, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x10, 0x05, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x52, 0x52, 0x5f,
+	0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x06, 0x32, 0xfa, 0x04, 0x0a, 0x0e, 0x50,
+	0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a,
+	0x0c, 0x53, 0x74, 0x61, 0x72, 0x74, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e,
+	0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x53, 0x74, 0x61, 0x72, 0x74, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x1a, 0x16, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x43,
+	0x6d, 0x52, 0x65, 0x73, 0x70, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0b,
+	0x53, 0x74, 0x6f, 0x70, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72,
+	0x66, 0x6f, 0x72, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x43,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x15, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65, 0x72,
+	0x66, 0x6f, 0x72, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3b, 0x0a, 0x09, 0x4b,
+	0x65, 0x65, 0x70, 0x41, 0x6c, 0x69, 0x76, 0x65, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f,
+	0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
+	0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x6f, 0x72, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3d, 0x0a, 0x0c, 0x50, 0x61, 0x75, 0x73,
+	0x65, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f,
+	0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
+	0x16, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x43, 0x6d, 0x52, 0x65, 0x73, 0x70,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x52, 0x65, 0x73, 0x75, 0x6d,
+	0x65, 0x50, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f,
+	0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x1a,
+	0x16, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x43, 0x6d, 0x52, 0x65, 0x73, 0x70,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x45, 0x0a, 0x0b, 0x53, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x53, 0x74, 0x61, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d,
+	0x2e, 0x53, 0x74, 0x61, 0x74, 0x73, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x50, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x30, 0x01, 0x12, 0x30,
+	0x0a, 0x06, 0x47, 0x65, 0x74, 0x52, 0x75, 0x6e, 0x12, 0x11, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f,
+	0x72, 0x6d, 0x2e, 0x52, 0x75, 0x6e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x1a, 0x13, 0x2e, 0x70, 0x65,
+	0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x75, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x3a, 0x0a, 0x08, 0x4c, 0x69, 0x73, 0x74, 0x52, 0x75, 0x6e, 0x73, 0x12, 0x15, 0x2e, 0x70,
+	0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x1a, 0x17, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x52, 0x75,
+	0x6e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x39, 0x0a, 0x09,
+	0x53, 0x79, 0x6e, 0x63, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66,
+	0x6f, 0x72, 0x6d, 0x2e, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x1a, 0x15, 0x2e, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x2e, 0x43, 0x6c, 0x6f, 0x63, 0x6b,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x42, 0x13, 0x5a, 0x11, 0x73, 0x72, 0x63, 0x2f, 0x70,
+	0x62, 0x3b, 0x70, 0x65, 0x72, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_perform_proto_rawDescOnce sync.Once
+	file_perform_proto_rawDescData = file_perform_proto_rawDesc
+)
+
+func file_perform_proto_rawDescGZIP() []byte {
+	file_perform_proto_rawDescOnce.Do(func() {
+		file_perform_proto_rawDescData = protoimpl.X.CompressGZIP(file_perform_proto_rawDescData)
+	})
+	return file_perform_proto_rawDescData
+}
+
+var file_perform_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_perform_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_perform_proto_goTypes = []any{
+	(Status)(0),                // 0: perform.Status
+	(ErrCode)(0),               // 1: perform.ErrCode
+	(*StartMessage)(nil),       // 2: perform.StartMessage
+	(*ClockMessage)(nil),       // 3: perform.ClockMessage
+	(*ExecutorStatus)(nil),     // 4: perform.ExecutorStatus
+	(*CmRespMessage)(nil),      // 5: perform.CmRespMessage
+	(*EmptyMessage)(nil),       // 6: perform.EmptyMessage
+	(*StatsStreamRequest)(nil), // 7: perform.StatsStreamRequest
+	(*PerformMessage)(nil),     // 8: perform.PerformMessage
+	(*PerformStats)(nil),       // 9: perform.PerformStats
+	(*Phase)(nil),              // 10: perform.Phase
+	(*Metric)(nil),             // 11: perform.Metric
+	(*GeneratorStats)(nil),     // 12: perform.GeneratorStats
+	(*Record)(nil),             // 13: perform.Record
+	(*RunQuery)(nil),           // 14: perform.RunQuery
+	(*Percentile)(nil),         // 15: perform.Percentile
+	(*RunSummary)(nil),         // 16: perform.RunSummary
+	(*PhaseSummary)(nil),       // 17: perform.PhaseSummary
+	(*MetricSummary)(nil),      // 18: perform.MetricSummary
+	(*RunInfo)(nil),            // 19: perform.RunInfo
+	(*RunMessage)(nil),         // 20: perform.RunMessage
+	(*RunListMessage)(nil),     // 21: perform.RunListMessage
+}
+var file_perform_proto_depIdxs = []int32{
+	0,  // 0: perform.ExecutorStatus.status:type_name -> perform.Status
+	9,  // 1: perform.PerformMessage.stats:type_name -> perform.PerformStats
+	13, // 2: perform.PerformStats.latency:type_name -> perform.Record
+	15, // 3: perform.PerformStats.percentiles:type_name -> perform.Percentile
+	12, // 4: perform.PerformStats.generator:type_name -> perform.GeneratorStats
+	11, // 5: perform.PerformStats.metrics:type_name -> perform.Metric
+	10, // 6: perform.PerformStats.phases:type_name -> perform.Phase
+	15, // 7: perform.RunSummary.percentiles:type_name -> perform.Percentile
+	18, // 8: perform.RunSummary.metrics:type_name -> perform.MetricSummary
+	17, // 9: perform.RunSummary.phases:type_name -> perform.PhaseSummary
+	15, // 10: perform.PhaseSummary.percentiles:type_name -> perform.Percentile
+	15, // 11: perform.MetricSummary.percentiles:type_name -> perform.Percentile
+	16, // 12: perform.RunInfo.summary:type_name -> perform.RunSummary
+	19, // 13: perform.RunMessage.run:type_name -> perform.RunInfo
+	19, // 14: perform.RunListMessage.runs:type_name -> perform.RunInfo
+	2,  // 15: perform.PerformService.StartPerform:input_type -> perform.StartMessage
+	6,  // 16: perform.PerformService.StopPerform:input_type -> perform.EmptyMessage
+	6,  // 17: perform.PerformService.CollectStats:input_type -> perform.EmptyMessage
+	6,  // 18: perform.PerformService.KeepAlive:input_type -> perform.EmptyMessage
+	6,  // 19: perform.PerformService.PausePerform:input_type -> perform.EmptyMessage
+	6,  // 20: perform.PerformService.ResumePerform:input_type -> perform.EmptyMessage
+	7,  // 21: perform.PerformService.StreamStats:input_type -> perform.StatsStreamRequest
+	14, // 22: perform.PerformService.GetRun:input_type -> perform.RunQuery
+	6,  // 23: perform.PerformService.ListRuns:input_type -> perform.EmptyMessage
+	3,  // 24: perform.PerformService.SyncClock:input_type -> perform.ClockMessage
+	5,  // 25: perform.PerformService.StartPerform:output_type -> perform.CmRespMessage
+	8,  // 26: perform.PerformService.StopPerform:output_type -> perform.PerformMessage
+	8,  // 27: perform.PerformService.CollectStats:output_type -> perform.PerformMessage
+	4,  // 28: perform.PerformService.KeepAlive:output_type -> perform.ExecutorStatus
+	5,  // 29: perform.PerformService.PausePerform:output_type -> perform.CmRespMessage
+	5,  // 30: perform.PerformService.ResumePerform:output_type -> perform.CmRespMessage
+	8,  // 31: perform.PerformService.StreamStats:output_type -> perform.PerformMessage
+	20, // 32: perform.PerformService.GetRun:output_type -> perform.RunMessage
+	21, // 33: perform.PerformService.ListRuns:output_type -> perform.RunListMessage
+	3,  // 34: perform.PerformService.SyncClock:output_type -> perform.ClockMessage
+	25, // [25:35] is the sub-list for method output_type
+	15, // [15:25] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_perform_proto_init() }
 func file_perform_proto_init() {
 	if File_perform_proto != nil {
 		return
@@ -560,8 +1940,8 @@ func file_perform_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_perform_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      2,
+			NumMessages:   20,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -574,4 +1954,4 @@ func file_perform_proto_init() {
 	file_perform_proto_rawDesc = nil
 	file_perform_proto_goTypes = nil
 	file_perform_proto_depIdxs = nil
-}
\ No newline at end of file
+}
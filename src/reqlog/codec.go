@@ -0,0 +1,304 @@
+package reqlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// binaryMagic 二进制格式的文件头
+const binaryMagic = "PFRLOG\x00\x01"
+
+// writer 按路径后缀选择格式写入请求日志：.bin 为二进制格式，其他为JSONL，.gz 结尾时gzip压缩
+type writer struct {
+	f   *os.File
+	gz  *gzip.Writer
+	buf *bufio.Writer
+	enc encoder
+}
+
+// encoder 请求日志的编码格式
+type encoder interface {
+	header(h *Header) error
+	encode(r *Record) error
+}
+
+func create(path string) (*writer, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create request log: %w", err)
+	}
+	w := &writer{f: f}
+	var out io.Writer = f
+	name := path
+	if strings.HasSuffix(name, ".gz") {
+		w.gz = gzip.NewWriter(f)
+		out = w.gz
+		name = strings.TrimSuffix(name, ".gz")
+	}
+	w.buf = bufio.NewWriterSize(out, 256*1024)
+	if strings.HasSuffix(name, ".bin") {
+		w.enc = &binaryEncoder{w: w.buf}
+	} else {
+		w.enc = &jsonEncoder{enc: json.NewEncoder(w.buf)}
+	}
+	return w, nil
+}
+
+func (w *writer) header(h *Header) error {
+	return w.enc.header(h)
+}
+
+func (w *writer) encode(r *Record) error {
+	return w.enc.encode(r)
+}
+
+// Close 写入缓冲的数据并关闭文件
+func (w *writer) Close() error {
+	err := w.buf.Flush()
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+	}
+	return errors.Join(err, w.f.Close())
+}
+
+// jsonEncoder 第一行为Header，之后每行一个Record
+type jsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *jsonEncoder) header(h *Header) error {
+	return e.enc.Encode(h)
+}
+
+func (e *jsonEncoder) encode(r *Record) error {
+	return e.enc.Encode(r)
+}
+
+// binaryEncoder 文件头之后是varint长度的Header JSON，之后每个Record依次为：
+// 与上一个请求开始时间的差值、协程序号、操作名、耗时、发送字节数、接收字节数、错误分类
+// 整数都是varint编码，操作名和错误分类是字符串表的下标，第一次出现时下标后面跟着长度和内容，错误分类的下标加1，0表示成功
+type binaryEncoder struct {
+	w    *bufio.Writer
+	prev int64
+	ops  map[string]uint64
+	errs map[string]uint64
+	tmp  [binary.MaxVarintLen64]byte
+}
+
+func (e *binaryEncoder) header(h *Header) error {
+	js, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if _, err := e.w.WriteString(binaryMagic); err != nil {
+		return err
+	}
+	e.uvarint(uint64(len(js)))
+	_, err = e.w.Write(js)
+	e.ops = make(map[string]uint64)
+	e.errs = make(map[string]uint64)
+	return err
+}
+
+func (e *binaryEncoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.tmp[:], v)
+	_, _ = e.w.Write(e.tmp[:n])
+}
+
+func (e *binaryEncoder) varint(v int64) {
+	n := binary.PutVarint(e.tmp[:], v)
+	_, _ = e.w.Write(e.tmp[:n])
+}
+
+// ref 写入字符串在表中的下标，第一次出现时写入内容，base为下标的偏移
+func (e *binaryEncoder) ref(table map[string]uint64, s string, base uint64) {
+	if idx, ok := table[s]; ok {
+		e.uvarint(idx + base)
+		return
+	}
+	idx := uint64(len(table))
+	table[s] = idx
+	e.uvarint(idx + base)
+	e.uvarint(uint64(len(s)))
+	_, _ = e.w.WriteString(s)
+}
+
+func (e *binaryEncoder) encode(r *Record) error {
+	e.varint(r.Time - e.prev)
+	e.prev = r.Time
+	e.uvarint(uint64(r.Goroutine))
+	e.ref(e.ops, r.Operation, 0)
+	e.varint(r.Latency)
+	e.varint(r.SendBytes)
+	e.varint(r.RecvBytes)
+	if r.Error == "" {
+		e.uvarint(0)
+	} else {
+		e.ref(e.errs, r.Error, 1)
+	}
+	// bufio.Writer 出错后保留错误，后续写入都会返回该错误
+	_, err := e.w.Write(nil)
+	return err
+}
+
+// Reader 读取请求日志，按文件内容识别gzip压缩和格式
+type Reader struct {
+	Header Header
+	f      *os.File
+	gz     *gzip.Reader
+	dec    decoder
+}
+
+type decoder interface {
+	decode(r *Record) error
+}
+
+// Open 打开请求日志并读取Header
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	rd := &Reader{f: f}
+	if err := rd.init(); err != nil {
+		_ = rd.Close()
+		return nil, fmt.Errorf("read request log %s: %w", path, err)
+	}
+	return rd, nil
+}
+
+func (rd *Reader) init() error {
+	br := bufio.NewReaderSize(rd.f, 256*1024)
+	if magic, _ := br.Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		rd.gz = gz
+		br = bufio.NewReaderSize(gz, 256*1024)
+	}
+	if magic, _ := br.Peek(len(binaryMagic)); string(magic) == binaryMagic {
+		_, _ = br.Discard(len(binaryMagic))
+		n, err := binary.ReadUvarint(br)
+		if err != nil {
+			return err
+		}
+		js := make([]byte, n)
+		if _, err := io.ReadFull(br, js); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(js, &rd.Header); err != nil {
+			return err
+		}
+		rd.dec = &binaryDecoder{r: br}
+	} else {
+		dec := json.NewDecoder(br)
+		if err := dec.Decode(&rd.Header); err != nil {
+			return err
+		}
+		rd.dec = &jsonDecoder{dec: dec}
+	}
+	if rd.Header.Version != Version {
+		return fmt.Errorf("unsupported request log version %d", rd.Header.Version)
+	}
+	return nil
+}
+
+// Next 读取下一个请求，读完时返回io.EOF
+func (rd *Reader) Next(r *Record) error {
+	return rd.dec.decode(r)
+}
+
+func (rd *Reader) Close() error {
+	var err error
+	if rd.gz != nil {
+		err = rd.gz.Close()
+	}
+	return errors.Join(err, rd.f.Close())
+}
+
+type jsonDecoder struct {
+	dec *json.Decoder
+}
+
+func (d *jsonDecoder) decode(r *Record) error {
+	*r = Record{}
+	return d.dec.Decode(r)
+}
+
+type binaryDecoder struct {
+	r    *bufio.Reader
+	prev int64
+	ops  []string
+	errs []string
+}
+
+// ref 读取字符串表的下标，第一次出现时读取内容，base为下标的偏移，小于base时返回空
+func (d *binaryDecoder) ref(table *[]string, base uint64) (string, error) {
+	idx, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return "", err
+	}
+	if idx < base {
+		return "", nil
+	}
+	idx -= base
+	if idx < uint64(len(*table)) {
+		return (*table)[idx], nil
+	}
+	if idx != uint64(len(*table)) {
+		return "", fmt.Errorf("invalid string index %d", idx)
+	}
+	n, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		return "", err
+	}
+	*table = append(*table, string(b))
+	return string(b), nil
+}
+
+func (d *binaryDecoder) decode(r *Record) error {
+	// 只有在记录的边界上读完才是正常结束
+	delta, err := binary.ReadVarint(d.r)
+	if err != nil {
+		return err
+	}
+	d.prev += delta
+	r.Time = d.prev
+	g, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return unexpected(err)
+	}
+	r.Goroutine = int64(g)
+	if r.Operation, err = d.ref(&d.ops, 0); err != nil {
+		return unexpected(err)
+	}
+	for _, v := range []*int64{&r.Latency, &r.SendBytes, &r.RecvBytes} {
+		if *v, err = binary.ReadVarint(d.r); err != nil {
+			return unexpected(err)
+		}
+	}
+	if r.Error, err = d.ref(&d.errs, 1); err != nil {
+		return unexpected(err)
+	}
+	return nil
+}
+
+// unexpected 记录中间读完说明文件被截断
+func unexpected(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package reqlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Version 请求日志的格式版本
+const Version = 1
+
+// DefaultBuffer 等待写入的请求数上限的默认值
+const DefaultBuffer = 65536
+
+// 内置的错误分类
+const (
+	ClassTimeout  = "timeout"
+	ClassCanceled = "canceled"
+	ClassEOF      = "eof"
+	ClassNetwork  = "network"
+	ClassPanic    = "panic"
+	ClassError    = "error"
+)
+
+// Record 一次请求的原始样本
+type Record struct {
+	// Time 请求开始的时间，unix时间戳(us)
+	Time int64 `json:"ts"`
+	// Goroutine 发压协程的序号
+	Goroutine int64  `json:"g"`
+	Operation string `json:"op"`
+	// Latency 请求耗时(us)，出错的请求同样记录
+	Latency   int64 `json:"lat"`
+	SendBytes int64 `json:"sent"`
+	RecvBytes int64 `json:"recv"`
+	// Error 错误分类，成功时为空
+	Error string `json:"err,omitempty"`
+}
+
+// Header 日志文件开头记录的压测信息
+type Header struct {
+	Version int    `json:"version"`
+	RunID   string `json:"run_id"`
+	Worker  string `json:"worker"`
+	Workers int64  `json:"workers"`
+	// Sample 采样比例，1表示记录了全部请求
+	Sample float64 `json:"sample"`
+	// StartAt 开始记录的时间，unix时间戳(us)
+	StartAt int64             `json:"start_at"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// Classifier 工作器返回的错误可以实现该接口自定义请求日志中的错误分类
+type Classifier interface {
+	ErrorClass() string
+}
+
+// Classify 返回错误的分类，err为nil时返回空
+func Classify(err error) string {
+	if err == nil {
+		return ""
+	}
+	var c Classifier
+	if errors.As(err, &c) {
+		return c.ErrorClass()
+	}
+	var ne net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &ne) && ne.Timeout():
+		return ClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ClassEOF
+	case errors.As(err, &ne):
+		return ClassNetwork
+	}
+	return ClassError
+}
+
+// requestLog 当前压测的请求日志，DoWorker只向有界队列投递，由单独的协程写入文件
+type requestLog struct {
+	path    string
+	sample  float64
+	ch      chan Record
+	stop    chan struct{}
+	done    chan struct{}
+	written int64
+	dropped atomic.Int64
+	err     error
+}
+
+var (
+	current atomic.Pointer[requestLog]
+	m       sync.Mutex
+)
+
+// Start 按配置开启请求日志，rc为nil或Path为空时不开启，返回的函数在压测结束时调用以写完剩余的请求并关闭文件
+func Start(rc *conf.RequestLogConf, h Header) (func(), error) {
+	if rc == nil || rc.Path == "" {
+		return func() {}, nil
+	}
+	path := strings.ReplaceAll(rc.Path, "{run_id}", h.RunID)
+	h.Version = Version
+	h.Sample = rc.Sample
+	if h.Sample <= 0 {
+		h.Sample = 1
+	}
+	h.StartAt = utils.GetTimeUs()
+	w, err := create(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.header(&h); err != nil {
+		_ = w.Close()
+		return nil, fmt.Errorf("write request log %s: %w", path, err)
+	}
+	size := rc.Buffer
+	if size <= 0 {
+		size = DefaultBuffer
+	}
+	l := &requestLog{
+		path:   path,
+		sample: h.Sample,
+		ch:     make(chan Record, size),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go l.loop(w)
+	m.Lock()
+	defer m.Unlock()
+	current.Store(l)
+	logger.Info("Log %.2f%% of requests to %s", h.Sample*100, path)
+	return func() {
+		m.Lock()
+		if current.Load() == l {
+			current.Store(nil)
+		}
+		m.Unlock()
+		close(l.stop)
+		<-l.done
+		if l.err != nil {
+			logger.Error("Write request log %s err: %v", path, l.err)
+		}
+		if d := l.dropped.Load(); d > 0 {
+			logger.Warning("Request log dropped %d records, the disk can not keep up, try a lower sample ratio", d)
+		}
+		logger.Info("Request log: %d records written to %s", l.written, path)
+	}, nil
+}
+
+// Sampled 本次请求是否需要记录，没有开启请求日志时返回false
+func Sampled() bool {
+	l := current.Load()
+	return l != nil && (l.sample >= 1 || rand.Float64() < l.sample)
+}
+
+// Log 投递一次请求，队列已满时丢弃，不阻塞发压
+func Log(r Record) {
+	l := current.Load()
+	if l == nil {
+		return
+	}
+	select {
+	case l.ch <- r:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+func (l *requestLog) loop(w *writer) {
+	defer close(l.done)
+	for {
+		select {
+		case r := <-l.ch:
+			l.write(w, &r)
+		case <-l.stop:
+			// 写完停止前已经投递的请求
+			for {
+				select {
+				case r := <-l.ch:
+					l.write(w, &r)
+				default:
+					if err := w.Close(); err != nil && l.err == nil {
+						l.err = err
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *requestLog) write(w *writer, r *Record) {
+	// 写入出错后不再写入，剩余的请求计为丢弃
+	if l.err != nil {
+		l.dropped.Add(1)
+		return
+	}
+	if err := w.encode(r); err != nil {
+		l.err = err
+		l.dropped.Add(1)
+		return
+	}
+	l.written++
+}
@@ -3,10 +3,13 @@ package runner
 import (
 	"context"
 	"errors"
+	"fmt"
 	"perform-cli-framework-go/src/conf"
 	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/reqlog"
 	"perform-cli-framework-go/src/stat"
 	"perform-cli-framework-go/src/stat/hdrImpl"
+	"perform-cli-framework-go/src/tracing"
 	"perform-cli-framework-go/src/utils"
 	"perform-cli-framework-go/src/worker"
 	"sync"
@@ -16,41 +19,155 @@ import (
 	"go.uber.org/ratelimit"
 )
 
+var (
+	ErrRunActive     = errors.New("benchmark already started")
+	ErrSetupFailed   = errors.New("global setup failed")
+	ErrNotRunning    = errors.New("benchmark not running")
+	ErrAlreadyPaused = errors.New("benchmark already paused")
+	ErrNotPaused     = errors.New("benchmark not paused")
+)
+
+// DrainResult 停止压测时排空阶段的统计
+type DrainResult struct {
+	// InFlight 开始排空时正在执行的请求数
+	InFlight int64
+	// Completed 排空期间执行完成的请求数
+	Completed int64
+	// Aborted 超过宽限期后被取消的请求数
+	Aborted int64
+	// Cost 排空耗时，单位微秒
+	Cost int64
+}
+
 type BenchMarkRunner struct {
 	running   atomic.Bool
+	draining  atomic.Bool
+	inFlight  atomic.Int64
+	drainDone atomic.Int64
 	sendM     sync.Mutex
 	startM    sync.Mutex
+	stopM     sync.Mutex
 	sendCount int64
+	drainUs   int64
 	stater    stat.Stater
 	call      *context.CancelFunc
-	c         *stat.IntervalStatistic
+	drain     *DrainResult
+	reqPerS   int64
+	pause     pauser
+	limiter   atomic.Pointer[ratelimit.Limiter]
+	rate      atomic.Int64
+	runs      runBook
+	hub       *statsHub
+	flushM    sync.Mutex
+	// statIntervalS 区间统计的切换周期，单位秒
+	statIntervalS atomic.Int64
+	// feeders 本次压测的数据源，setup时加载
+	feeders map[string]*conf.Feeder
+	// active 正在发压的协程数
+	active atomic.Int64
+	// quiet 为true时不在控制台周期打印区间统计，例如终端仪表盘接管了输出
+	quiet atomic.Bool
+	// taken 区间内从限速器取得的发压次数，用于计算落后于目标速率的时长
+	taken atomic.Int64
+	// monitor 压测机自身资源使用的采样
+	monitor selfMonitor
+	// trace 压测配置中没有指定链路追踪时使用的配置
+	trace *conf.TraceConf
+	// stopTrace 导出剩余的span并关闭本次压测的链路追踪
+	stopTrace func()
+	// reqLog 压测配置中没有指定请求日志时使用的配置
+	reqLog *conf.RequestLogConf
+	// stopReqLog 写完剩余的请求并关闭本次压测的请求日志
+	stopReqLog func()
+	// hlogPath 压测配置中没有指定直方图日志时使用的路径
+	hlogPath string
+	// hlog 本次压测的区间直方图日志
+	hlog *hlogFile
+	// sinkSpecs 压测配置中没有指定输出目标时使用的输出目标
+	sinkSpecs []string
+	// sinks 本次压测的输出目标
+	sinks []*openedSink
+	// onFinish 压测结束后以压测记录的快照调用，例如保存到结果库
+	onFinish func(run RunInfo)
 }
 
 func NewBenchRunner(t int64) *BenchMarkRunner {
-	return &BenchMarkRunner{
+	b := &BenchMarkRunner{
 		stater: hdrImpl.New(t),
+		hub:    newStatsHub(),
 	}
+	b.statIntervalS.Store(1)
+	return b
+}
+
+// SetTrace 设置默认的链路追踪配置，压测配置中指定了trace时以压测配置为准
+func (b *BenchMarkRunner) SetTrace(tc *conf.TraceConf) {
+	b.trace = tc
+}
+
+// SetRequestLog 设置默认的请求日志配置，压测配置中指定了requestLog时以压测配置为准
+func (b *BenchMarkRunner) SetRequestLog(rc *conf.RequestLogConf) {
+	b.reqLog = rc
+}
+
+// SetFinishHook 设置压测结束后的回调，在压测协程中同步调用
+func (b *BenchMarkRunner) SetFinishHook(fn func(run RunInfo)) {
+	b.onFinish = fn
 }
 
 func (b *BenchMarkRunner) IsRunning() bool {
 	return b.running.Load() == true
 }
 
+// Percentiles 当前或最近一次压测配置的分位数
+func (b *BenchMarkRunner) Percentiles() []float64 {
+	if run := b.runs.get(""); run != nil {
+		return run.Config.ReportPercentiles()
+	}
+	return stat.SummaryPercentiles
+}
+
+// InFlight 正在执行的请求数
+func (b *BenchMarkRunner) InFlight() int64 {
+	return b.inFlight.Load()
+}
+
+// ActiveWorkers 正在发压的协程数
+func (b *BenchMarkRunner) ActiveWorkers() int64 {
+	return b.active.Load()
+}
+
+// SetQuiet 关闭或打开控制台的周期统计打印
+func (b *BenchMarkRunner) SetQuiet(quiet bool) {
+	b.quiet.Store(quiet)
+}
+
+// CachedStatistics 返回最近一个区间的统计
 func (b *BenchMarkRunner) CachedStatistics() *stat.IntervalStatistic {
-	return b.c
+	return b.hub.cached()
 }
 
+// LastDrain 返回最近一次停止时的排空统计，未停止过时为nil
+func (b *BenchMarkRunner) LastDrain() *DrainResult {
+	b.stopM.Lock()
+	defer b.stopM.Unlock()
+	return b.drain
+}
+
+// GetStatistics 返回自上次调用以来合并的区间统计，供轮询方式的CollectStats使用
 func (b *BenchMarkRunner) GetStatistics() *stat.IntervalStatistic {
-	b.c = b.stater.GetIntervalStatistic()
-	return b.c
+	return b.hub.takePoll()
 }
 
-func (b *BenchMarkRunner) mainLoop(c context.Context, data *conf.GoData, r ratelimit.Limiter, workerHand worker.Worker, wait *sync.WaitGroup) {
+func (b *BenchMarkRunner) mainLoop(c context.Context, data *conf.GoData, workerHand worker.Worker, wait *sync.WaitGroup) {
+	b.active.Add(1)
 	// 获取自己实现的Worker
 	defer func() {
+		b.active.Add(-1)
 		defer func() {
 			if p := recover(); p != nil {
 				logger.Error("Worker with unknown error: %v,exit benchmark", p)
+				b.runs.fail(b.runs.active(), fmt.Sprintf("worker panic: %v", p))
 				b.Stop()
 			}
 		}()
@@ -67,9 +184,11 @@ func (b *BenchMarkRunner) mainLoop(c context.Context, data *conf.GoData, r ratel
 		case <-c.Done():
 			return
 		default:
-			if data.Cfg.Rate > 0 && data.RateLimiter != nil {
-				r.Take()
+			if b.pause.paused.Load() {
+				b.pause.wait(c)
+				continue
 			}
+			b.take()
 			if data.Cfg.Nums > 0 {
 				b.sendM.Lock()
 				if b.sendCount >= data.Cfg.Nums {
@@ -79,7 +198,23 @@ func (b *BenchMarkRunner) mainLoop(c context.Context, data *conf.GoData, r ratel
 				b.sendCount++
 				b.sendM.Unlock()
 			}
+			// 在限速器中等待时可能已经停止，先计入执行中再检查，Stop 要么看到这个请求，要么这里看到已停止
+			b.inFlight.Add(1)
+			if !b.running.Load() || c.Err() != nil {
+				b.inFlight.Add(-1)
+				if data.Cfg.Nums > 0 {
+					// 归还没有发出的请求个数
+					b.sendM.Lock()
+					b.sendCount--
+					b.sendM.Unlock()
+				}
+				return
+			}
 			err1 := workerHand.DoWorker(data)
+			b.inFlight.Add(-1)
+			if b.draining.Load() {
+				b.drainDone.Add(1)
+			}
 			if err1 == worker.ExitError {
 				return
 			}
@@ -87,54 +222,208 @@ func (b *BenchMarkRunner) mainLoop(c context.Context, data *conf.GoData, r ratel
 	}
 }
 
-func (b *BenchMarkRunner) StartAsync(config conf.BenchConfig) {
-	ctx, cc := context.WithCancel(context.Background())
-	b.call = &cc
+// StartAsync 同步完成配置校验和全局前置后异步执行压测，返回本次压测的ID
+// 全局前置失败时同样返回压测ID，该压测以setup-failed状态记录在历史中
+func (b *BenchMarkRunner) StartAsync(config conf.BenchConfig) (string, error) {
+	ctx, run, workerHand, err := b.setup(context.Background(), config)
+	if err != nil {
+		if run != nil {
+			return run.ID, err
+		}
+		return "", err
+	}
 	go func(config conf.BenchConfig) {
-		// 服务形式的不能挂
+		// 服务形式的不能挂，压测中的 panic 已在 run 中记录为失败，这里只兜底汇总时的 panic
 		defer func() {
 			if p := recover(); p != nil {
 				logger.Error("Run with Fatal err: %v", p)
 				b.running.Store(false)
+				b.runs.transit(run, RunFailed, fmt.Sprintf("%v", p))
 			}
 		}()
-		err := b.Start(ctx, config)
+		err := b.run(ctx, run, workerHand, config)
 		if err != nil {
 			logger.Error("Run benchmark err: %v", err)
 		}
 	}(config)
+	return run.ID, nil
 }
 
 // Start 启动压测
 func (b *BenchMarkRunner) Start(ctx context.Context, cfg conf.BenchConfig) error {
-	worker.ResetStatus()
+	ctx, run, workerHand, err := b.setup(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return b.run(ctx, run, workerHand, cfg)
+}
+
+// setup 校验配置、登记压测并执行全局前置
+func (b *BenchMarkRunner) setup(ctx context.Context, cfg conf.BenchConfig) (context.Context, *RunInfo, worker.Worker, error) {
+	if err := cfg.Check(); err != nil {
+		return nil, nil, nil, err
+	}
+	workerHand, err := worker.NewWorker(cfg.WorkerName)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	feeders, err := conf.OpenFeeders(cfg.Feeders)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx, run, err := b.prepare(ctx, cfg)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	b.feeders = feeders
+	tc := cfg.Trace
+	if tc == nil {
+		tc = b.trace
+	}
+	b.stopTrace, err = tracing.Start(ctx, tc)
+	if err != nil {
+		b.stopTrace = func() {}
+		b.cleanup()
+		b.runs.transit(run, RunSetupFailed, err.Error())
+		logger.SetField("run_id", "")
+		return nil, run, nil, fmt.Errorf("%w: trace: %v", ErrSetupFailed, err)
+	}
+	rc := cfg.RequestLog
+	if rc == nil {
+		rc = b.reqLog
+	}
+	b.stopReqLog, err = reqlog.Start(rc, reqlog.Header{RunID: run.ID, Worker: cfg.WorkerName, Workers: cfg.Workers, Tags: cfg.Tags})
+	if err != nil {
+		b.stopReqLog = func() {}
+		b.cleanup()
+		b.stopTrace()
+		b.runs.transit(run, RunSetupFailed, err.Error())
+		logger.SetField("run_id", "")
+		return nil, run, nil, fmt.Errorf("%w: request log: %v", ErrSetupFailed, err)
+	}
+	if err = b.openSinks(run, cfg); err != nil {
+		b.cleanup()
+		b.stopTrace()
+		b.stopReqLog()
+		b.runs.transit(run, RunSetupFailed, err.Error())
+		logger.SetField("run_id", "")
+		return nil, run, nil, fmt.Errorf("%w: %v", ErrSetupFailed, err)
+	}
+	if err = b.createHlog(run, cfg); err != nil {
+		b.cleanup()
+		b.stopTrace()
+		b.stopReqLog()
+		b.closeSinks(nil)
+		b.runs.transit(run, RunSetupFailed, err.Error())
+		logger.SetField("run_id", "")
+		return nil, run, nil, fmt.Errorf("%w: %v", ErrSetupFailed, err)
+	}
+	// 执行全局前置
+	err = b.setupGlobal(ctx, workerHand, cfg)
+	if err != nil {
+		b.cleanup()
+		b.stopTrace()
+		b.stopReqLog()
+		b.closeSinks(nil)
+		b.flushM.Lock()
+		b.closeHlog()
+		b.flushM.Unlock()
+		b.runs.transit(run, RunSetupFailed, err.Error())
+		logger.SetField("run_id", "")
+		return nil, run, nil, fmt.Errorf("%w: %v", ErrSetupFailed, err)
+	}
+	return ctx, run, workerHand, nil
+}
+
+func (b *BenchMarkRunner) setupGlobal(ctx context.Context, workerHand worker.Worker, cfg conf.BenchConfig) (err error) {
+	// 全局前置在rpc协程中执行，panic不能让执行器退出
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return workerHand.SetupGlobal(ctx, cfg)
+}
+
+// prepare 登记新的压测，已经有压测在进行时返回ErrRunActive
+func (b *BenchMarkRunner) prepare(ctx context.Context, cfg conf.BenchConfig) (context.Context, *RunInfo, error) {
 	b.startM.Lock()
-	start := utils.GetTimeUs()
-	goDataS := make([]*conf.GoData, cfg.Workers)
-	var r ratelimit.Limiter
-	if cfg.Rate > 0 {
-		r = ratelimit.New(int(cfg.Rate))
+	defer b.startM.Unlock()
+	run, err := b.runs.begin(cfg)
+	if err != nil {
+		// 已经在执行则退出
+		logger.Warning("Benchmark started, ignore: %v", err)
+		return nil, nil, fmt.Errorf("%w: %v", ErrRunActive, err)
 	}
-	var wait sync.WaitGroup
-	wait.Add(int(cfg.Workers))
+	logger.SetField("run_id", run.ID)
+	logger.SetField("worker", cfg.WorkerName)
+	worker.ResetStatus()
 	b.sendCount = 0
-	workerHand := worker.NewWorker(cfg.WorkerName)
-	if b.running.Load() {
-		// 已经在执行则释放锁退出
-		logger.Warning("Benchmark started, ignore")
-		b.startM.Unlock()
-		return errors.New("benchmark started, ignore")
+	b.reqPerS = 0
+	b.pause.reset()
+	// 丢弃上一次压测未被取走的统计，避免混入本次压测
+	b.hub.takePoll()
+	if cfg.StatInterval > 0 {
+		b.statIntervalS.Store(cfg.StatInterval)
 	}
 	b.running.Store(true)
-	b.startM.Unlock()
-	// 执行全局前置
-	err := workerHand.SetupGlobal(ctx, cfg)
-	if err != nil {
-		return err
+	// 排空结束后通过该cancel取消仍在执行的请求
+	ctx, cc := context.WithCancel(ctx)
+	b.stopM.Lock()
+	b.call = &cc
+	b.drain = nil
+	b.drainUs = cfg.Drain().Microseconds()
+	b.stopM.Unlock()
+	return ctx, run, nil
+}
+
+// cleanup 停止发压并取消context
+func (b *BenchMarkRunner) cleanup() {
+	b.running.Store(false)
+	b.stopM.Lock()
+	if b.call != nil {
+		(*b.call)()
+		b.call = nil
 	}
+	b.stopM.Unlock()
+}
+
+func (b *BenchMarkRunner) run(ctx context.Context, run *RunInfo, workerHand worker.Worker, cfg conf.BenchConfig) error {
+	start := utils.GetTimeUs()
+	goDataS := make([]*conf.GoData, cfg.Workers)
+	var samples []stat.IntervalSample
 	defer func() {
-		b.running.Store(false)
+		summary := b.stater.GetSummary(cfg.ReportPercentiles())
+		summary.LogSelf()
 		b.stater.Reset()
+		b.cleanup()
+		b.stopTrace()
+		b.stopReqLog()
+		b.closeSinks(summary)
+		b.flushM.Lock()
+		b.closeHlog()
+		b.flushM.Unlock()
+		b.runs.finish(run, &RunResult{
+			Complete:  b.completed(goDataS),
+			Durations: utils.GetTimeUs() - start - b.pause.totalPaused(),
+			ReqPerS:   b.reqPerS,
+			Latency:   summary,
+			Drain:     b.LastDrain(),
+			Intervals: samples,
+		})
+		if b.onFinish != nil {
+			if snap := b.runs.get(run.ID); snap != nil {
+				b.onFinish(*snap)
+			}
+		}
+		logger.SetField("run_id", "")
+	}()
+	// 在汇总之前恢复 panic 并记录原因，使压测以失败状态结束
+	defer func() {
+		if p := recover(); p != nil {
+			logger.Error("Run with Fatal err: %v", p)
+			b.runs.fail(run, fmt.Sprintf("%v", p))
+		}
 	}()
 	defer func() {
 		// 全局后置
@@ -144,11 +433,40 @@ func (b *BenchMarkRunner) Start(ctx context.Context, cfg conf.BenchConfig) error
 			return
 		}
 	}()
+	// 全局前置已完成，等待统一的开始时间
+	if cfg.StartAt > 0 {
+		b.runs.transit(run, RunReady, "")
+		if late := utils.GetTimeUs() - cfg.StartAt; late > 0 {
+			logger.Warning("Start time passed %d ms ago, start now", late/1000)
+		} else {
+			logger.Info("Ready, start in %d ms @%d", -late/1000, cfg.StartAt)
+		}
+		if !waitUntil(ctx, cfg.StartAt) {
+			logger.Info("Run %s stopped before start", run.ID)
+			return nil
+		}
+		start = utils.GetTimeUs()
+	}
+	b.setRate(cfg.Schedule()[0].Rate)
+	r := b.limiter.Load()
+	var wait sync.WaitGroup
+	wait.Add(int(cfg.Workers))
+	b.runs.transit(run, RunRunning, "")
+	// 丢弃启动前空闲时间的区间，让第一个区间从压测开始计时
+	b.flushM.Lock()
+	idle := b.stater.GetIntervalStatistic()
+	b.taken.Store(0)
+	b.monitor.reset()
+	b.startHlog(run, cfg, idle.Histogram.EndTimeMs())
+	b.flushM.Unlock()
 	for i := int64(0); i < cfg.Workers; i++ {
 		data := &conf.GoData{
 			Cfg:         cfg,
-			RateLimiter: &r,
+			RateLimiter: r,
 			SendTotal:   0,
+			Feeders:     b.feeders,
+			RunID:       run.ID,
+			Goroutine:   i,
 		}
 		// 这里这个context是用来做强制退出的的一般网络库都会一个ctx给客户端做主动退出
 		data.Ctx = ctx
@@ -156,60 +474,107 @@ func (b *BenchMarkRunner) Start(ctx context.Context, cfg conf.BenchConfig) error
 		data.StaterI = b.stater
 		// 保证全局初始化的数据全部传输成功
 		tmpW := workerHand.Clone()
-		go b.mainLoop(ctx, goDataS[i], r, tmpW, &wait)
+		go b.mainLoop(ctx, goDataS[i], tmpW, &wait)
 	}
 	if cfg.Nums > 0 {
 		logger.Info("Running %d Nums test @%d", cfg.Nums, start)
 	} else {
-		logger.Info("Running %d s test @%d", cfg.Duration, start)
+		logger.Info("Running %d s test @%d", cfg.TotalDuration(), start)
 	}
 	logger.Info("  %d goroutines", cfg.Workers)
-	go func() { // 若是没有指定发送的数据就指定时间
-		if cfg.Nums == 0 {
-			time.Sleep(time.Duration(cfg.Duration) * time.Second)
-			b.Stop()
-		}
-	}()
+	if cfg.Nums == 0 {
+		// 若是没有指定发送的数据就按阶段的时间
+		go b.schedule(ctx, cfg)
+	}
+	go b.produceStatistics(ctx)
 	// 启动一个协程打印临时的压测统计
+	printer := b.SubscribeStatistics(max(int(cfg.ReportEvery()/b.StatInterval()), 1))
+	printed := make(chan struct{})
 	go func() {
-		for b.running.Load() {
-			time.Sleep(time.Second * 30)
-			// 未启动grpc服务的时候，自己打印压测统计到控制台
-			var ss *stat.IntervalStatistic
-			if !cfg.GrpcCfg.Enable {
-				ss = b.GetStatistics()
-			} else {
-				ss = b.CachedStatistics()
+		defer close(printed)
+		for ss := range printer.C {
+			if !b.quiet.Load() {
+				ss.LogSelf(cfg.ReportPercentiles())
 			}
-			if ss != nil {
-				ss.LogSelf()
+		}
+	}()
+	// 记录每个区间的摘要，随压测结果保存，同时推送到输出目标
+	sampler := b.SubscribeStatistics(1)
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for ss := range sampler.C {
+			if s := ss.Sample(cfg.ReportPercentiles()); s != nil && len(samples) < maxIntervalSamples {
+				samples = append(samples, *s)
 			}
+			b.pushSinks(ss)
 		}
 	}()
 	wait.Wait()
-	complete := int64(0)
-	for _, v := range goDataS {
-		complete += v.SendTotal
-	}
-	runtimeUs := utils.GetTimeUs() - start
+	// 补齐最后一个区间，再关闭控制台打印和区间摘要的记录
+	b.flushStatistics()
+	b.Unsubscribe(printer)
+	b.Unsubscribe(sampler)
+	<-printed
+	<-sampled
+	complete := b.completed(goDataS)
+	runtimeUs := utils.GetTimeUs() - start - b.pause.totalPaused()
 	runtimeS := runtimeUs / 1000000.0
 	if runtimeS == 0 {
 		// 没有运行1s按1s算
 		runtimeS = 1
 	}
 	reqPerS := complete / runtimeS
+	b.reqPerS = reqPerS
 	logger.Info("Complete request: %d", complete)
 	logger.Info("Test durations: %d s", runtimeS)
 	logger.Info("Requests/sec: %d", reqPerS)
-	logger.Info("Bye. @%d", utils.GetTimeUs())
+	if d := b.LastDrain(); d != nil {
+		logger.Info("Drain: %d ms, in-flight %d, completed %d, aborted %d",
+			d.Cost/1000, d.InFlight, d.Completed, d.Aborted)
+	}
+	logger.Info("Run %s bye. @%d", run.ID, utils.GetTimeUs())
 	return nil
 }
 
+func (b *BenchMarkRunner) completed(goDataS []*conf.GoData) int64 {
+	complete := int64(0)
+	for _, v := range goDataS {
+		if v != nil {
+			complete += v.SendTotal
+		}
+	}
+	return complete
+}
+
+// Stop 停止压测：先停止发起新的请求，在宽限期内等待执行中的请求完成，超时后再取消context
 func (b *BenchMarkRunner) Stop() {
+	b.stopM.Lock()
+	defer b.stopM.Unlock()
 	b.running.Store(false)
-	time.Sleep(1 * time.Second)
-	if b.call != nil {
-		(*b.call)()
-		b.call = nil
+	if b.call == nil {
+		return
+	}
+	b.runs.transit(b.runs.active(), RunDraining, "")
+	// 暂停中的协程没有执行中的请求，排空后随cancel一起退出
+	b.drain = b.drainInFlight()
+	(*b.call)()
+	b.call = nil
+}
+
+// drainInFlight 等待执行中的请求完成，最多等待drainUs微秒
+func (b *BenchMarkRunner) drainInFlight() *DrainResult {
+	b.drainDone.Store(0)
+	b.draining.Store(true)
+	defer b.draining.Store(false)
+	start := utils.GetTimeUs()
+	res := &DrainResult{InFlight: b.inFlight.Load()}
+	deadline := start + b.drainUs
+	for b.inFlight.Load() > 0 && utils.GetTimeUs() < deadline {
+		time.Sleep(10 * time.Millisecond)
 	}
+	res.Aborted = b.inFlight.Load()
+	res.Completed = b.drainDone.Load()
+	res.Cost = utils.GetTimeUs() - start
+	return res
 }
@@ -0,0 +1,14 @@
+//go:build unix
+
+package runner
+
+import "syscall"
+
+// cpuTimeUs 进程累计使用的CPU时间(用户态+内核态)，单位微秒
+func cpuTimeUs() int64 {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0
+	}
+	return ru.Utime.Nano()/1000 + ru.Stime.Nano()/1000
+}
@@ -0,0 +1,23 @@
+//go:build windows
+
+package runner
+
+import "syscall"
+
+// cpuTimeUs 进程累计使用的CPU时间(用户态+内核态)，单位微秒
+func cpuTimeUs() int64 {
+	h, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return 0
+	}
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0
+	}
+	// Filetime 的单位为100纳秒
+	return (filetime(kernel) + filetime(user)) / 10
+}
+
+func filetime(ft syscall.Filetime) int64 {
+	return int64(ft.HighDateTime)<<32 | int64(ft.LowDateTime)
+}
@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"strings"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// hlogFile 本次压测的区间直方图日志，只在flushStatistics和run中使用，由flushM保护
+type hlogFile struct {
+	f    *os.File
+	w    *stat.HlogWriter
+	path string
+	n    int
+}
+
+// SetHlog 设置默认的直方图日志路径，压测配置中指定了hlog时以压测配置为准
+func (b *BenchMarkRunner) SetHlog(path string) {
+	b.hlogPath = path
+}
+
+// createHlog 在全局前置之前创建直方图日志，路径为空时不写入，创建失败时压测以 setup-failed 结束
+func (b *BenchMarkRunner) createHlog(run *RunInfo, cfg conf.BenchConfig) error {
+	path := cfg.Hlog
+	if path == "" {
+		path = b.hlogPath
+	}
+	if path == "" {
+		return nil
+	}
+	path = strings.ReplaceAll(path, "{run_id}", run.ID)
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create hlog: %w", err)
+	}
+	b.flushM.Lock()
+	b.hlog = &hlogFile{f: f, path: path}
+	b.flushM.Unlock()
+	return nil
+}
+
+// startHlog 开始发压时写入日志头，startMs为第一个区间的开始时间，需要持有flushM
+func (b *BenchMarkRunner) startHlog(run *RunInfo, cfg conf.BenchConfig, startMs int64) {
+	hl := b.hlog
+	if hl == nil {
+		return
+	}
+	comment := fmt.Sprintf("perform-cli-framework-go %s, run %s, worker %s, latency in microseconds", utils.Version, run.ID, cfg.WorkerName)
+	w, err := stat.NewHlogWriter(hl.f, startMs, utils.Instance(), comment)
+	if err != nil {
+		logger.Error("Write hlog %s err: %v", hl.path, err)
+		b.closeHlog()
+		return
+	}
+	hl.w = w
+	logger.Info("Write interval histograms to %s", hl.path)
+}
+
+// writeHlog 写入一个区间直方图，出错时关闭日志不再写入
+func (b *BenchMarkRunner) writeHlog(h *hdrhistogram.Histogram) {
+	hl := b.hlog
+	if hl == nil || hl.w == nil || h == nil {
+		return
+	}
+	if err := hl.w.Write(h); err != nil {
+		logger.Error("Write hlog %s err: %v", hl.path, err)
+		b.closeHlog()
+		return
+	}
+	hl.n++
+}
+
+func (b *BenchMarkRunner) closeHlog() {
+	hl := b.hlog
+	if hl == nil {
+		return
+	}
+	b.hlog = nil
+	if err := hl.f.Close(); err != nil {
+		logger.Error("Close hlog %s err: %v", hl.path, err)
+		return
+	}
+	logger.Info("Hlog: %d intervals written to %s", hl.n, hl.path)
+}
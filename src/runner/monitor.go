@@ -0,0 +1,237 @@
+package runner
+
+import (
+	"math"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"runtime"
+	"runtime/metrics"
+)
+
+const (
+	// schedLatencyMetric 协程调度延迟的累计直方图
+	schedLatencyMetric = "/sched/latencies:seconds"
+	// gcPauseMetric GC导致的stop-the-world暂停时长的累计直方图
+	gcPauseMetric = "/sched/pauses/total/gc:seconds"
+	// gcCyclesMetric 完成的GC次数
+	gcCyclesMetric = "/gc/cycles/total:gc-cycles"
+	// goroutinesMetric 当前的协程数
+	goroutinesMetric = "/sched/goroutines:goroutines"
+	// saturatedLagRatio 区间内落后时长超过实际发压时长的该比例时视为跟不上目标速率
+	saturatedLagRatio = 0.1
+	// saturatedWarnEvery 持续跟不上时每隔多少个区间重复告警一次
+	saturatedWarnEvery = 10
+)
+
+// 采样的指标在 selfMonitor.samples 中的下标
+const (
+	sampleSched = iota
+	sampleGCPause
+	sampleGCCycles
+	sampleGoroutines
+)
+
+// selfMonitor 在每个统计区间采样压测机自身的资源使用，只在flushStatistics中使用，由flushM保护
+// 通过 runtime/metrics 采样，不使用会 stop-the-world 的 runtime.ReadMemStats
+type selfMonitor struct {
+	wall      int64
+	cpu       int64
+	numGC     uint64
+	samples   []metrics.Sample
+	schedPrev []uint64
+	pausePrev []uint64
+	saturated bool
+	// warned 持续跟不上目标速率的区间数，用于控制告警频率
+	warned int
+}
+
+// reset 以当前时刻作为下一个区间的起点
+func (m *selfMonitor) reset() {
+	m.wall = utils.GetTimeUs()
+	m.cpu = cpuTimeUs()
+	m.samples = []metrics.Sample{
+		sampleSched:      {Name: schedLatencyMetric},
+		sampleGCPause:    {Name: gcPauseMetric},
+		sampleGCCycles:   {Name: gcCyclesMetric},
+		sampleGoroutines: {Name: goroutinesMetric},
+	}
+	metrics.Read(m.samples)
+	m.numGC = m.uint64Sample(sampleGCCycles)
+	m.schedPrev, m.pausePrev = nil, nil
+	if h := m.histogram(sampleSched); h != nil {
+		m.schedPrev = append([]uint64(nil), h.Counts...)
+	}
+	if h := m.histogram(sampleGCPause); h != nil {
+		m.pausePrev = append([]uint64(nil), h.Counts...)
+	}
+	m.saturated = false
+	m.warned = 0
+}
+
+func (m *selfMonitor) histogram(i int) *metrics.Float64Histogram {
+	if len(m.samples) <= i || m.samples[i].Value.Kind() != metrics.KindFloat64Histogram {
+		return nil
+	}
+	return m.samples[i].Value.Float64Histogram()
+}
+
+// uint64Sample 当前Go版本不支持该指标时返回0
+func (m *selfMonitor) uint64Sample(i int) uint64 {
+	if len(m.samples) <= i || m.samples[i].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return m.samples[i].Value.Uint64()
+}
+
+// sample 采样自上次采样以来的资源使用
+func (m *selfMonitor) sample() *stat.GeneratorStats {
+	if m.samples == nil {
+		m.reset()
+	}
+	metrics.Read(m.samples)
+	g := &stat.GeneratorStats{
+		Cores:      int64(runtime.NumCPU()),
+		Goroutines: int64(m.uint64Sample(sampleGoroutines)),
+	}
+	now, cpu := utils.GetTimeUs(), cpuTimeUs()
+	if wall := now - m.wall; wall > 0 {
+		g.CPU = float64(cpu-m.cpu) / float64(wall) * 100
+	}
+	m.wall, m.cpu = now, cpu
+	numGC := m.uint64Sample(sampleGCCycles)
+	g.GCCount = int64(numGC - m.numGC)
+	m.numGC = numGC
+	if h := m.histogram(sampleGCPause); h != nil {
+		g.GCPause, g.GCPauseMax = gcPause(h, m.pausePrev)
+		m.pausePrev = append(m.pausePrev[:0], h.Counts...)
+	}
+	if h := m.histogram(sampleSched); h != nil {
+		g.SchedLatencyP99, g.SchedLatencyMax = schedLatency(h, m.schedPrev)
+		m.schedPrev = append(m.schedPrev[:0], h.Counts...)
+	}
+	return g
+}
+
+// histDelta 两次累计直方图各个桶的差值以及总数
+func histDelta(h *metrics.Float64Histogram, prev []uint64) ([]uint64, uint64) {
+	total := uint64(0)
+	delta := make([]uint64, len(h.Counts))
+	for i, c := range h.Counts {
+		if i < len(prev) {
+			c -= prev[i]
+		}
+		delta[i] = c
+		total += c
+	}
+	return delta, total
+}
+
+// bucketBound 桶i的上界，单位微秒
+// 桶i的范围是[Buckets[i], Buckets[i+1])，最后一个桶的上界可能是+Inf，取下界
+func bucketBound(h *metrics.Float64Histogram, i int) int64 {
+	v := h.Buckets[i+1]
+	if math.IsInf(v, 1) {
+		v = h.Buckets[i]
+	}
+	return int64(v * 1000 * 1000)
+}
+
+// schedLatency 用两次累计直方图的差值计算区间内调度延迟的p99和最大值，单位微秒
+func schedLatency(h *metrics.Float64Histogram, prev []uint64) (int64, int64) {
+	delta, total := histDelta(h, prev)
+	if total == 0 {
+		return 0, 0
+	}
+	var p99, maxLatency int64
+	target := uint64(math.Ceil(float64(total) * 0.99))
+	count := uint64(0)
+	for i, c := range delta {
+		if c == 0 {
+			continue
+		}
+		count += c
+		if p99 == 0 && count >= target {
+			p99 = bucketBound(h, i)
+		}
+		maxLatency = bucketBound(h, i)
+	}
+	return p99, maxLatency
+}
+
+// gcPause 用两次累计直方图的差值计算区间内GC暂停的总时长和最长一次暂停，单位微秒
+// 直方图只记录所在的桶，总时长按桶的中点估算，最长暂停取所在桶的上界
+func gcPause(h *metrics.Float64Histogram, prev []uint64) (int64, int64) {
+	delta, total := histDelta(h, prev)
+	if total == 0 {
+		return 0, 0
+	}
+	var sum float64
+	var maxPause int64
+	for i, c := range delta {
+		if c == 0 {
+			continue
+		}
+		lo, hi := h.Buckets[i], h.Buckets[i+1]
+		if math.IsInf(lo, -1) {
+			lo = 0
+		}
+		if math.IsInf(hi, 1) {
+			hi = lo
+		}
+		sum += float64(c) * (lo + hi) / 2
+		maxPause = bucketBound(h, i)
+	}
+	return int64(sum * 1000 * 1000), maxPause
+}
+
+// limiterLag 区间内实际发压落后于目标速率的时长(us)，activeUs为区间内实际发压的时长
+func (b *BenchMarkRunner) limiterLag(activeUs int64) int64 {
+	taken := b.taken.Swap(0)
+	rate := b.rate.Load()
+	if rate <= 0 || activeUs <= 0 || b.active.Load() == 0 {
+		return 0
+	}
+	expected := float64(rate) * float64(activeUs) / (1000 * 1000)
+	if deficit := expected - float64(taken); deficit > 0 {
+		return int64(deficit / float64(rate) * 1000 * 1000)
+	}
+	return 0
+}
+
+// checkSaturation 压测机跟不上目标速率时告警，并给出可能的原因
+func (b *BenchMarkRunner) checkSaturation(g *stat.GeneratorStats, activeUs int64) {
+	m := &b.monitor
+	// 发压协程都已退出(压测结束)时不再判断
+	if b.active.Load() == 0 {
+		return
+	}
+	g.Saturated = activeUs > 0 && g.LimiterLag > int64(float64(activeUs)*saturatedLagRatio)
+	if !g.Saturated {
+		if m.saturated {
+			logger.Info("[Generator] Caught up with the target rate %d req/s", g.TargetRate)
+		}
+		m.saturated, m.warned = false, 0
+		return
+	}
+	if m.saturated && m.warned%saturatedWarnEvery != 0 {
+		m.warned++
+		return
+	}
+	m.saturated = true
+	m.warned++
+	var reason string
+	inFlight, workers := b.inFlight.Load(), b.active.Load()
+	switch {
+	case inFlight >= workers:
+		reason = "all workers are waiting for responses, the target may be slow or more workers (-w) are needed"
+	case g.CPU >= float64(g.Cores)*100*0.9:
+		reason = "the executor is CPU bound"
+	case g.SchedLatencyP99 >= 10*1000 || g.GCPause >= activeUs/10:
+		reason = "the executor is suffering from scheduler latency or GC pauses"
+	default:
+		reason = "the worker may block between requests"
+	}
+	logger.Warning("!!! [Generator] Can not keep up with the target rate %d req/s, behind %s in %s, %d/%d workers busy, CPU %.0f%%: %s",
+		g.TargetRate, stat.FormatLatency(g.LimiterLag), stat.FormatLatency(activeUs), inFlight, workers, g.CPU, reason)
+}
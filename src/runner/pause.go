@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"context"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/utils"
+	"sync"
+	"sync/atomic"
+)
+
+// pauser 暂停控制，暂停期间协程和worker的Setup状态都保留，只是不再发起请求
+type pauser struct {
+	paused atomic.Bool
+	m      sync.Mutex
+	// resume 暂停时创建，恢复时关闭用来唤醒所有协程
+	resume chan struct{}
+	// begin 本次暂停开始的时间，未暂停时为0
+	begin int64
+	// interval 自上次取统计以来累计的暂停时长
+	interval int64
+	// total 本次压测累计的暂停时长
+	total int64
+}
+
+func (p *pauser) reset() {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.resume != nil {
+		close(p.resume)
+		p.resume = nil
+	}
+	p.paused.Store(false)
+	p.begin = 0
+	p.interval = 0
+	p.total = 0
+}
+
+func (p *pauser) pause() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if p.paused.Load() {
+		return ErrAlreadyPaused
+	}
+	p.resume = make(chan struct{})
+	p.begin = utils.GetTimeUs()
+	p.paused.Store(true)
+	return nil
+}
+
+func (p *pauser) unpause() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	if !p.paused.Load() {
+		return ErrNotPaused
+	}
+	d := utils.GetTimeUs() - p.begin
+	p.interval += d
+	p.total += d
+	p.begin = 0
+	p.paused.Store(false)
+	close(p.resume)
+	p.resume = nil
+	return nil
+}
+
+// wait 暂停时阻塞直到恢复或者ctx取消
+func (p *pauser) wait(c context.Context) {
+	p.m.Lock()
+	ch := p.resume
+	p.m.Unlock()
+	if ch == nil {
+		return
+	}
+	select {
+	case <-ch:
+	case <-c.Done():
+	}
+}
+
+// takeInterval 返回自上次调用以来的暂停时长并重新计数
+func (p *pauser) takeInterval() int64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	d := p.interval
+	if p.paused.Load() {
+		now := utils.GetTimeUs()
+		d += now - p.begin
+		p.total += now - p.begin
+		p.begin = now
+	}
+	p.interval = 0
+	return d
+}
+
+// totalPaused 返回本次压测累计的暂停时长
+func (p *pauser) totalPaused() int64 {
+	p.m.Lock()
+	defer p.m.Unlock()
+	d := p.total
+	if p.paused.Load() {
+		d += utils.GetTimeUs() - p.begin
+	}
+	return d
+}
+
+// Pause 暂停压测，保留协程以及已累计的统计数据
+func (b *BenchMarkRunner) Pause() error {
+	if !b.running.Load() {
+		return ErrNotRunning
+	}
+	if err := b.pause.pause(); err != nil {
+		return err
+	}
+	logger.Info("Benchmark paused")
+	return nil
+}
+
+// Resume 恢复被暂停的压测
+func (b *BenchMarkRunner) Resume() error {
+	if !b.running.Load() {
+		return ErrNotRunning
+	}
+	if err := b.pause.unpause(); err != nil {
+		return err
+	}
+	logger.Info("Benchmark resumed")
+	return nil
+}
+
+func (b *BenchMarkRunner) IsPaused() bool {
+	return b.pause.paused.Load()
+}
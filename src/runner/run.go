@@ -0,0 +1,220 @@
+package runner
+
+import (
+	"fmt"
+	"math/rand"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"sync"
+)
+
+// RunState 压测的状态
+type RunState int
+
+const (
+	RunStarting RunState = iota
+	RunSetupFailed
+	RunReady
+	RunRunning
+	RunDraining
+	RunFinished
+	RunFailed
+)
+
+var runStateNames = map[RunState]string{
+	RunStarting:    "starting",
+	RunSetupFailed: "setup-failed",
+	RunReady:       "ready",
+	RunRunning:     "running",
+	RunDraining:    "draining",
+	RunFinished:    "finished",
+	RunFailed:      "failed",
+}
+
+func (s RunState) String() string {
+	if n, ok := runStateNames[s]; ok {
+		return n
+	}
+	return fmt.Sprintf("unknown(%d)", int(s))
+}
+
+// MarshalText 以名称序列化，便于阅读保存的压测记录
+func (s RunState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *RunState) UnmarshalText(b []byte) error {
+	for k, v := range runStateNames {
+		if v == string(b) {
+			*s = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown run state %s", string(b))
+}
+
+// Terminal 是否为结束状态
+func (s RunState) Terminal() bool {
+	return s == RunSetupFailed || s == RunFinished || s == RunFailed
+}
+
+// RunResult 压测结束后的汇总
+type RunResult struct {
+	Complete int64
+	// Durations 实际发压时长，不含暂停，单位微秒
+	Durations int64
+	ReqPerS   int64
+	Latency   *stat.Summary
+	Drain     *DrainResult
+	// Intervals 每个统计区间的摘要，用于对比两次压测时做统计检验，超过 maxIntervalSamples 个时不再记录
+	Intervals []stat.IntervalSample
+}
+
+// maxIntervalSamples 一次压测保存的区间摘要个数上限，1s的区间约可覆盖3小时
+const maxIntervalSamples = 10000
+
+// RunInfo 一次压测的信息，通过快照对外暴露
+type RunInfo struct {
+	ID      string
+	State   RunState
+	Reason  string
+	Config  conf.BenchConfig
+	StartAt int64
+	EndAt   int64
+	Result  *RunResult
+}
+
+// maxRunHistory 保留的历史压测个数
+const maxRunHistory = 32
+
+// runBook 当前压测以及历史压测的记录
+type runBook struct {
+	m       sync.Mutex
+	current *RunInfo
+	history []*RunInfo
+}
+
+func newRunID() string {
+	return fmt.Sprintf("%d-%04x", utils.GetTimeUs()/1000, rand.Intn(0x10000))
+}
+
+// begin 没有进行中的压测时登记一个新的压测
+func (rb *runBook) begin(cfg conf.BenchConfig) (*RunInfo, error) {
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	if rb.current != nil && !rb.current.State.Terminal() {
+		return nil, fmt.Errorf("run %s is %s", rb.current.ID, rb.current.State)
+	}
+	run := &RunInfo{
+		ID:      newRunID(),
+		State:   RunStarting,
+		Config:  cfg,
+		StartAt: utils.GetTimeUs(),
+	}
+	rb.current = run
+	rb.history = append(rb.history, run)
+	if len(rb.history) > maxRunHistory {
+		rb.history = rb.history[len(rb.history)-maxRunHistory:]
+	}
+	return run, nil
+}
+
+// transit 切换状态，结束状态不会再被改变
+func (rb *runBook) transit(run *RunInfo, state RunState, reason string) {
+	if run == nil {
+		return
+	}
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	if run.State.Terminal() {
+		return
+	}
+	// 已经记录了失败原因的以失败结束
+	if state == RunFinished && run.Reason != "" {
+		state = RunFailed
+	}
+	run.State = state
+	if reason != "" && run.Reason == "" {
+		run.Reason = reason
+	}
+	if state.Terminal() {
+		run.EndAt = utils.GetTimeUs()
+	}
+}
+
+// fail 记录失败原因，压测结束时以失败状态结束
+func (rb *runBook) fail(run *RunInfo, reason string) {
+	if run == nil {
+		return
+	}
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	if !run.State.Terminal() && run.Reason == "" {
+		run.Reason = reason
+	}
+}
+
+func (rb *runBook) finish(run *RunInfo, res *RunResult) {
+	rb.m.Lock()
+	run.Result = res
+	rb.m.Unlock()
+	rb.transit(run, RunFinished, "")
+}
+
+func (rb *runBook) active() *RunInfo {
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	if rb.current != nil && !rb.current.State.Terminal() {
+		return rb.current
+	}
+	return nil
+}
+
+func (rb *runBook) snapshot(run *RunInfo) *RunInfo {
+	if run == nil {
+		return nil
+	}
+	cp := *run
+	return &cp
+}
+
+// get 按ID查找，ID为空时返回最近一次压测
+func (rb *runBook) get(id string) *RunInfo {
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	if id == "" {
+		return rb.snapshot(rb.current)
+	}
+	for _, r := range rb.history {
+		if r.ID == id {
+			return rb.snapshot(r)
+		}
+	}
+	return nil
+}
+
+func (rb *runBook) list() []*RunInfo {
+	rb.m.Lock()
+	defer rb.m.Unlock()
+	res := make([]*RunInfo, 0, len(rb.history))
+	for i := len(rb.history) - 1; i >= 0; i-- {
+		res = append(res, rb.snapshot(rb.history[i]))
+	}
+	return res
+}
+
+// CurrentRun 返回最近一次压测的快照，没有压测过时为nil
+func (b *BenchMarkRunner) CurrentRun() *RunInfo {
+	return b.runs.get("")
+}
+
+// GetRun 按ID返回压测的快照，ID为空时返回最近一次压测
+func (b *BenchMarkRunner) GetRun(id string) *RunInfo {
+	return b.runs.get(id)
+}
+
+// ListRuns 返回历史压测的快照，最近的在前
+func (b *BenchMarkRunner) ListRuns() []*RunInfo {
+	return b.runs.list()
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/utils"
+	"time"
+
+	"go.uber.org/ratelimit"
+)
+
+// setRate 切换发压速率，rate为0时不限速
+func (b *BenchMarkRunner) setRate(rate int64) {
+	b.rate.Store(max(rate, 0))
+	if rate <= 0 {
+		b.limiter.Store(nil)
+		return
+	}
+	l := ratelimit.New(int(rate))
+	b.limiter.Store(&l)
+}
+
+// Rate 当前的目标速率，0表示不限速
+func (b *BenchMarkRunner) Rate() int64 {
+	return b.rate.Load()
+}
+
+// SetRate 调整正在进行的压测的速率，进入下一个阶段时会被阶段的速率覆盖
+func (b *BenchMarkRunner) SetRate(rate int64) error {
+	if !b.running.Load() {
+		return ErrNotRunning
+	}
+	if rate < 0 {
+		return fmt.Errorf("%w: rate must not be negative, got %d", conf.ErrInvalidConfig, rate)
+	}
+	b.setRate(rate)
+	logger.Info("Rate adjusted to %d req/s", rate)
+	return nil
+}
+
+// take 按当前速率等待下一次发压
+func (b *BenchMarkRunner) take() {
+	if l := b.limiter.Load(); l != nil {
+		(*l).Take()
+		b.taken.Add(1)
+	}
+}
+
+// waitUntil 等待到指定的时间点，ctx取消时返回false
+func waitUntil(ctx context.Context, at int64) bool {
+	remain := at - utils.GetTimeUs()
+	if remain <= 0 {
+		return true
+	}
+	timer := time.NewTimer(time.Duration(remain) * time.Microsecond)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sleepActive 等待实际发压的时间达到us微秒，暂停的时间不计入，ctx取消时返回false
+func (b *BenchMarkRunner) sleepActive(ctx context.Context, us int64) bool {
+	end := utils.GetTimeUs() + us - b.pause.totalPaused()
+	for {
+		at := end + b.pause.totalPaused()
+		if at <= utils.GetTimeUs() {
+			return true
+		}
+		if !waitUntil(ctx, at) {
+			return false
+		}
+	}
+}
+
+// schedule 按阶段切换速率，全部阶段结束后停止压测
+func (b *BenchMarkRunner) schedule(ctx context.Context, cfg conf.BenchConfig) {
+	stages := cfg.Schedule()
+	for i, st := range stages {
+		if i > 0 {
+			b.setRate(st.Rate)
+		}
+		if len(stages) > 1 {
+			logger.Info("Stage %d/%d: %d s @%d req/s", i+1, len(stages), st.Duration, st.Rate)
+		}
+		if !b.sleepActive(ctx, st.Duration*1000*1000) {
+			// 已经被提前停止，避免误停后续的压测
+			return
+		}
+	}
+	b.Stop()
+}
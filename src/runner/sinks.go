@@ -0,0 +1,75 @@
+package runner
+
+import (
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/sink"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+)
+
+// openedSink 本次压测的一个输出目标，只在推送协程和压测结束时使用
+type openedSink struct {
+	spec   string
+	s      sink.Sink
+	failed int64
+}
+
+// SetSinks 设置默认的输出目标，压测配置中指定了sinks时以压测配置为准
+func (b *BenchMarkRunner) SetSinks(specs []string) {
+	b.sinkSpecs = specs
+}
+
+// openSinks 创建本次压测的输出目标
+func (b *BenchMarkRunner) openSinks(run *RunInfo, cfg conf.BenchConfig) error {
+	specs := cfg.Sinks
+	if len(specs) == 0 {
+		specs = b.sinkSpecs
+	}
+	b.sinks = nil
+	sinks, err := sink.Open(specs, sink.Run{
+		ID:          run.ID,
+		Worker:      cfg.WorkerName,
+		Instance:    utils.Instance(),
+		Tags:        cfg.Tags,
+		Percentiles: cfg.ReportPercentiles(),
+	})
+	if err != nil {
+		return err
+	}
+	for i, s := range sinks {
+		b.sinks = append(b.sinks, &openedSink{spec: specs[i], s: s})
+		logger.Info("Push interval stats to %s", specs[i])
+	}
+	return nil
+}
+
+// pushSinks 把区间统计推送到所有输出目标，每个输出目标只打印第一次失败
+func (b *BenchMarkRunner) pushSinks(ss *stat.IntervalStatistic) {
+	for _, o := range b.sinks {
+		if err := o.s.Interval(ss); err != nil {
+			if o.failed == 0 {
+				logger.Warning("Push interval stats to %s err: %v", o.spec, err)
+			}
+			o.failed++
+		}
+	}
+}
+
+// closeSinks 推送最终汇总并关闭输出目标，summary为nil时只关闭
+func (b *BenchMarkRunner) closeSinks(summary *stat.Summary) {
+	for _, o := range b.sinks {
+		if summary != nil {
+			if err := o.s.Summary(summary); err != nil {
+				logger.Warning("Push summary to %s err: %v", o.spec, err)
+			}
+		}
+		if o.failed > 0 {
+			logger.Warning("Sink %s: %d pushes failed", o.spec, o.failed)
+		}
+		if err := o.s.Close(); err != nil {
+			logger.Warning("Close sink %s err: %v", o.spec, err)
+		}
+	}
+	b.sinks = nil
+}
@@ -0,0 +1,148 @@
+package runner
+
+import (
+	"context"
+	"perform-cli-framework-go/src/stat"
+	"sync"
+	"time"
+)
+
+// StatsSubscription 区间统计的订阅，每累计every个区间推送一次合并后的统计
+type StatsSubscription struct {
+	C       <-chan *stat.IntervalStatistic
+	c       chan *stat.IntervalStatistic
+	id      int64
+	every   int
+	n       int
+	pending *stat.IntervalStatistic
+}
+
+// statsHub 由唯一的生产者定时切换区间统计，再分发给所有订阅者，避免多个调用方互相抢走区间数据
+type statsHub struct {
+	m    sync.Mutex
+	seq  int64
+	subs map[int64]*StatsSubscription
+	// poll 自上次CollectStats以来累计的统计
+	poll *stat.IntervalStatistic
+	last *stat.IntervalStatistic
+}
+
+func newStatsHub() *statsHub {
+	return &statsHub{subs: make(map[int64]*StatsSubscription)}
+}
+
+func (h *statsHub) subscribe(every int) *StatsSubscription {
+	if every <= 0 {
+		every = 1
+	}
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.seq++
+	c := make(chan *stat.IntervalStatistic, 16)
+	sub := &StatsSubscription{C: c, c: c, id: h.seq, every: every}
+	h.subs[sub.id] = sub
+	return sub
+}
+
+// unsubscribe 推送还没有凑满every个区间的统计后关闭channel，最后一个区间不会丢失
+func (h *statsHub) unsubscribe(sub *StatsSubscription) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	if _, ok := h.subs[sub.id]; ok {
+		delete(h.subs, sub.id)
+		if sub.pending != nil {
+			// publish 总是留出一个空位，这里不会阻塞
+			sub.c <- sub.pending
+			sub.pending = nil
+		}
+		close(sub.c)
+	}
+}
+
+func (h *statsHub) publish(ss *stat.IntervalStatistic) {
+	h.m.Lock()
+	defer h.m.Unlock()
+	h.last = ss
+	h.poll = stat.Merge(h.poll, ss)
+	for _, sub := range h.subs {
+		sub.pending = stat.Merge(sub.pending, ss)
+		sub.n++
+		// 只有持有锁时才会发送，留出的一个空位给取消订阅时推送剩余的统计
+		if sub.n < sub.every || len(sub.c) >= cap(sub.c)-1 {
+			// 订阅者消费太慢时继续合并，数据不丢只是推送粒度变粗
+			continue
+		}
+		sub.c <- sub.pending
+		sub.pending = nil
+		sub.n = 0
+	}
+}
+
+// takePoll 返回自上次调用以来累计的统计
+func (h *statsHub) takePoll() *stat.IntervalStatistic {
+	h.m.Lock()
+	defer h.m.Unlock()
+	ss := h.poll
+	h.poll = nil
+	if ss == nil {
+		ss = &stat.IntervalStatistic{}
+		if h.last != nil {
+			ss.SendTotal = h.last.SendTotal
+			ss.ErrorTotal = h.last.ErrorTotal
+			ss.Paused = h.last.Paused
+		}
+	}
+	return ss
+}
+
+func (h *statsHub) cached() *stat.IntervalStatistic {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.last
+}
+
+// SubscribeStatistics 订阅区间统计，every为合并推送的区间个数
+func (b *BenchMarkRunner) SubscribeStatistics(every int) *StatsSubscription {
+	return b.hub.subscribe(every)
+}
+
+// Unsubscribe 取消订阅并关闭订阅的channel
+func (b *BenchMarkRunner) Unsubscribe(sub *StatsSubscription) {
+	b.hub.unsubscribe(sub)
+}
+
+// StatInterval 返回生产者切换区间的周期
+func (b *BenchMarkRunner) StatInterval() time.Duration {
+	return time.Duration(b.statIntervalS.Load()) * time.Second
+}
+
+// produceStatistics 唯一的区间统计生产者，压测结束时退出
+func (b *BenchMarkRunner) produceStatistics(ctx context.Context) {
+	ticker := time.NewTicker(b.StatInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.flushStatistics()
+		}
+	}
+}
+
+// flushStatistics 切换一次区间统计并分发
+func (b *BenchMarkRunner) flushStatistics() {
+	b.flushM.Lock()
+	defer b.flushM.Unlock()
+	ss := b.stater.GetIntervalStatistic()
+	ss.PausedDurations = b.pause.takeInterval()
+	ss.Paused = b.pause.paused.Load()
+	activeUs := ss.Durations - ss.PausedDurations
+	g := b.monitor.sample()
+	g.TargetRate = b.rate.Load()
+	g.LimiterLag = b.limiterLag(activeUs)
+	b.checkSaturation(g, activeUs)
+	ss.Generator = g
+	b.writeHlog(ss.Histogram)
+	b.hub.publish(ss)
+}
@@ -0,0 +1,326 @@
+package scenario
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// 指标的判定结果
+const (
+	VerdictOK     = "ok"
+	VerdictBetter = "better"
+	// VerdictWorse 超出容差但统计上不显著，可能是波动，不计为回退
+	VerdictWorse      = "worse"
+	VerdictRegression = "REGRESSION"
+)
+
+const (
+	// DefaultErrorRateTolerance error_rate 的默认容差，是错误率的绝对差值
+	DefaultErrorRateTolerance = 0.001
+	// DefaultAlpha 统计检验的显著性水平
+	DefaultAlpha = 0.05
+)
+
+// CompareSpectrum 对比直方图日志时默认的分位数谱
+var CompareSpectrum = []float64{50, 75, 90, 95, 99, 99.9, 99.99}
+
+// compareInfoMetrics 只展示不判定的指标，总请求数取决于压测时长，最小值没有参考意义
+var compareInfoMetrics = map[string]bool{"requests": true, "min": true}
+
+// CompareOptions 对比的容差和统计检验参数
+type CompareOptions struct {
+	// Tolerance 默认的相对容差，error_rate 除外
+	Tolerance float64
+	// Tolerances 按指标指定的容差，error_rate 为绝对差值，其他为相对值
+	Tolerances map[string]float64
+	Alpha      float64
+	// Metrics 参与判定的指标，为空时判定全部指标
+	Metrics []string
+	// Warmup 跳过开始这段时长(us)内的区间，不参与统计检验
+	Warmup int64
+}
+
+// tolerance 返回指标的容差
+func (o *CompareOptions) tolerance(metric string) float64 {
+	if t, ok := o.Tolerances[metric]; ok {
+		return t
+	}
+	if metric == "error_rate" {
+		return DefaultErrorRateTolerance
+	}
+	return o.Tolerance
+}
+
+// CompareRow 单个指标的对比
+type CompareRow struct {
+	Metric  string  `json:"metric"`
+	Base    float64 `json:"base"`
+	Current float64 `json:"current"`
+	// Delta 相对基线的变化比例，基线为0时为0
+	Delta     float64 `json:"delta"`
+	Tolerance float64 `json:"tolerance,omitempty"`
+	// PValue 区间样本的 Mann-Whitney U 检验的p值，样本不足时为空
+	PValue *float64 `json:"p_value,omitempty"`
+	// Verdict 判定结果，不参与判定的指标为空
+	Verdict string `json:"verdict,omitempty"`
+}
+
+// Comparison 两次压测的对比结果
+type Comparison struct {
+	Rows []CompareRow `json:"rows"`
+	// BaseSamples、CurrentSamples 参与统计检验的区间数
+	BaseSamples    int     `json:"base_samples"`
+	CurrentSamples int     `json:"current_samples"`
+	Alpha          float64 `json:"alpha"`
+	// Regressions 判定为回退的指标
+	Regressions []string `json:"regressions"`
+	Pass        bool     `json:"pass"`
+}
+
+// Compare 对比两次压测的吞吐、错误率和时延
+// 指标的变化超出容差，并且两次压测的区间样本在统计上有显著差异时判定为回退，区间样本不足时只按容差判定
+func Compare(base, current *runner.RunResult, o CompareOptions) *Comparison {
+	if o.Alpha <= 0 {
+		o.Alpha = DefaultAlpha
+	}
+	metrics := []string{"requests", "rps", "error_rate", "min", "mean", "max"}
+	if base.Latency != nil {
+		for _, p := range base.Latency.Percentiles {
+			if p.Percentile < 100 {
+				metrics = append(metrics, stat.PercentileName(p.Percentile))
+			}
+		}
+	}
+	gated := make(map[string]bool, len(o.Metrics))
+	for _, m := range o.Metrics {
+		gated[m] = true
+	}
+	bs, cs := testSamples(base.Intervals, o.Warmup), testSamples(current.Intervals, o.Warmup)
+	cmp := &Comparison{BaseSamples: len(bs), CurrentSamples: len(cs), Alpha: o.Alpha, Regressions: make([]string, 0), Pass: true}
+	for _, m := range metrics {
+		b, err1 := MetricValue(m, base)
+		c, err2 := MetricValue(m, current)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		row := CompareRow{Metric: m, Base: b, Current: c}
+		if b != 0 {
+			row.Delta = (c - b) / b
+		}
+		if compareInfoMetrics[m] || len(gated) > 0 && !gated[m] {
+			cmp.Rows = append(cmp.Rows, row)
+			continue
+		}
+		row.Tolerance = o.tolerance(m)
+		p := stat.MannWhitney(sampleValues(bs, m), sampleValues(cs, m))
+		if !math.IsNaN(p) {
+			row.PValue = &p
+		}
+		row.Verdict = judge(m, b, c, row.Tolerance, p, o.Alpha)
+		if row.Verdict == VerdictRegression {
+			cmp.Regressions = append(cmp.Regressions, m)
+			cmp.Pass = false
+		}
+		cmp.Rows = append(cmp.Rows, row)
+	}
+	return cmp
+}
+
+// judge 判定单个指标，吞吐越低越差，错误率和时延越高越差
+func judge(metric string, base, current, tolerance, p, alpha float64) string {
+	worse := current - base
+	if metric == "rps" {
+		worse = -worse
+	}
+	// error_rate 的容差是绝对差值，其他指标按相对基线的比例
+	if metric != "error_rate" {
+		switch {
+		case base != 0:
+			worse /= math.Abs(base)
+		case worse > 0:
+			worse = math.Inf(1)
+		case worse < 0:
+			worse = math.Inf(-1)
+		}
+	}
+	switch {
+	case worse > tolerance:
+		if math.IsNaN(p) || p < alpha {
+			return VerdictRegression
+		}
+		return VerdictWorse
+	case worse < -tolerance:
+		return VerdictBetter
+	}
+	return VerdictOK
+}
+
+// testSamples 返回参与统计检验的区间，跳过预热的区间和时长不足中位数一半的区间(例如最后一个不完整的区间)
+func testSamples(intervals []stat.IntervalSample, warmup int64) []stat.IntervalSample {
+	if len(intervals) == 0 {
+		return nil
+	}
+	from := intervals[0].Start + warmup/1000
+	res := make([]stat.IntervalSample, 0, len(intervals))
+	for _, s := range intervals {
+		if s.Start >= from {
+			res = append(res, s)
+		}
+	}
+	if len(res) == 0 {
+		return nil
+	}
+	durations := make([]int64, 0, len(res))
+	for _, s := range res {
+		durations = append(durations, s.Durations)
+	}
+	sort.Slice(durations, func(a, b int) bool {
+		return durations[a] < durations[b]
+	})
+	median := durations[len(durations)/2]
+	full := res[:0]
+	for _, s := range res {
+		if s.Durations*2 >= median {
+			full = append(full, s)
+		}
+	}
+	return full
+}
+
+// sampleValues 取出区间样本中的指标值，没有该指标的区间被跳过
+func sampleValues(samples []stat.IntervalSample, metric string) []float64 {
+	res := make([]float64, 0, len(samples))
+	for i := range samples {
+		if v, ok := samples[i].Value(metric); ok {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// PrintCompare 以表格形式打印对比结果
+func PrintCompare(w io.Writer, cmp *Comparison) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "metric\tbase\tcurrent\tdelta\ttolerance\tp-value\tverdict\t")
+	for _, r := range cmp.Rows {
+		tol, p, verdict := "-", "-", "-"
+		if r.Verdict != "" {
+			verdict = r.Verdict
+			tol = fmt.Sprintf("%.2f%%", r.Tolerance*100)
+			if r.Metric == "error_rate" {
+				tol = fmt.Sprintf("%gpp", r.Tolerance*100)
+			}
+		}
+		if r.PValue != nil {
+			p = fmt.Sprintf("%.4f", *r.PValue)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%+.2f%%\t%s\t%s\t%s\t\n",
+			r.Metric, formatNum(r.Base), formatNum(r.Current), r.Delta*100, tol, p, verdict)
+	}
+	_ = tw.Flush()
+	_, _ = fmt.Fprintf(w, "Interval samples: base %d, current %d", cmp.BaseSamples, cmp.CurrentSamples)
+	if cmp.BaseSamples < stat.MinTestSamples || cmp.CurrentSamples < stat.MinTestSamples {
+		_, _ = fmt.Fprintf(w, ", less than %d, verdicts use the tolerance only", stat.MinTestSamples)
+	} else {
+		_, _ = fmt.Fprintf(w, ", significance level %g", cmp.Alpha)
+	}
+	_, _ = fmt.Fprintln(w)
+	if cmp.Pass {
+		_, _ = fmt.Fprintln(w, "Result: PASS")
+		return
+	}
+	_, _ = fmt.Fprintf(w, "Result: FAIL, regressions: %s\n", strings.Join(cmp.Regressions, ", "))
+}
+
+// formatNum 整数不带小数，其他保留4位小数
+func formatNum(v float64) string {
+	if v == float64(int64(v)) {
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return strconv.FormatFloat(v, 'f', 4, 64)
+}
+
+// ParseTolerance 解析容差，可以写 0.05 或 5%
+func ParseTolerance(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	scale := 1.0
+	if v, ok := strings.CutSuffix(s, "%"); ok {
+		s, scale = v, 0.01
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid tolerance %q, want e.g. 0.05 or 5%%", s)
+	}
+	return v * scale, nil
+}
+
+// ParseTolerances 解析按指标的容差，例如 p99=10%,error_rate=0.2%
+func ParseTolerances(s string) (map[string]float64, error) {
+	res := make(map[string]float64)
+	for _, kv := range strings.Split(s, ",") {
+		kv = strings.TrimSpace(kv)
+		if kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tolerance %q, want metric=value", kv)
+		}
+		t, err := ParseTolerance(v)
+		if err != nil {
+			return nil, err
+		}
+		res[strings.TrimSpace(k)] = t
+	}
+	return res, nil
+}
+
+// LoadResult 读取用于对比的压测结果，.hlog 结尾时从区间直方图日志还原，其他按报告读取
+// 直方图日志没有错误信息，时延按percentiles输出分位数
+func LoadResult(path string, percentiles []float64) (*runner.RunResult, error) {
+	if !strings.HasSuffix(path, ".hlog") {
+		rp, err := ReadReport(path)
+		if err != nil {
+			return nil, err
+		}
+		return rp.Run.Result, nil
+	}
+	hs, err := stat.ReadHlog(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(hs) == 0 {
+		return nil, fmt.Errorf("hlog %s has no interval histograms", path)
+	}
+	tags := make(map[string]bool)
+	for _, h := range hs {
+		tags[h.Tag()] = true
+	}
+	if len(tags) > 1 {
+		// 合并后的多个执行器的日志，按第一个区间的时长把同一周期的直方图合并为一个区间
+		if hs, err = stat.MergeHlogs([]string{path}, max(hs[0].EndTimeMs()-hs[0].StartTimeMs(), 1)); err != nil {
+			return nil, err
+		}
+	}
+	var total *hdrhistogram.Histogram
+	res := &runner.RunResult{}
+	for _, h := range hs {
+		total = stat.MergeHistogram(total, h)
+		ss := &stat.IntervalStatistic{Histogram: h, Durations: (h.EndTimeMs() - h.StartTimeMs()) * 1000}
+		res.Intervals = append(res.Intervals, *ss.Sample(percentiles))
+	}
+	res.Complete = total.TotalCount()
+	res.Durations = (hs[len(hs)-1].EndTimeMs() - hs[0].StartTimeMs()) * 1000
+	res.ReqPerS = res.Complete / max(res.Durations/1000/1000, 1)
+	res.Latency = (&stat.IntervalStatistic{Histogram: total, SendTotal: res.Complete}).LatencySummary(percentiles)
+	return res, nil
+}
@@ -0,0 +1,75 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/runner"
+)
+
+// Output 压测结束后输出结果的位置
+// type 为 json 时把压测记录和条件检查结果写入 path，为 stdout 时打印到标准输出
+type Output struct {
+	Type string `json:"type" yaml:"type"`
+	Path string `json:"path" yaml:"path"`
+}
+
+func (o *Output) check() error {
+	switch o.Type {
+	case "json":
+		if o.Path == "" {
+			return fmt.Errorf("%w: json output requires path", conf.ErrInvalidConfig)
+		}
+	case "stdout":
+	default:
+		return fmt.Errorf("%w: unknown output type %s", conf.ErrInvalidConfig, o.Type)
+	}
+	return nil
+}
+
+// Report 场景执行的结果
+type Report struct {
+	Scenario   string            `json:"scenario"`
+	Run        *runner.RunInfo   `json:"run"`
+	Thresholds []ThresholdResult `json:"thresholds,omitempty"`
+	Pass       bool              `json:"pass"`
+}
+
+// Finish 检查条件并输出结果，返回是否满足所有条件
+func (s *Scenario) Finish(run *runner.RunInfo) (*Report, error) {
+	rp := &Report{Scenario: s.Name, Run: run}
+	var result *runner.RunResult
+	if run != nil {
+		result = run.Result
+	}
+	rp.Thresholds, rp.Pass = Evaluate(s.Thresholds, result)
+	// 压测失败时即使满足所有条件也不算通过
+	if run != nil && run.State != runner.RunFinished {
+		rp.Pass = false
+	}
+	for _, t := range rp.Thresholds {
+		if t.Pass {
+			logger.Info("Threshold %s: pass (%v)", t.String(), t.Actual)
+		} else {
+			logger.Warning("Threshold %s: fail (%v)", t.String(), t.Actual)
+		}
+	}
+	js, err := json.MarshalIndent(rp, "", "  ")
+	if err != nil {
+		return rp, err
+	}
+	for _, o := range s.Outputs {
+		switch o.Type {
+		case "json":
+			if err := os.WriteFile(o.Path, js, 0644); err != nil {
+				return rp, fmt.Errorf("write output %s: %w", o.Path, err)
+			}
+			logger.Info("Result written to %s", o.Path)
+		case "stdout":
+			fmt.Println(string(js))
+		}
+	}
+	return rp, nil
+}
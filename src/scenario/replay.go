@@ -0,0 +1,234 @@
+package scenario
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/reqlog"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// maxReplayLatency 回放时直方图可以记录的最大时延(us)，超过时按该值记录
+const maxReplayLatency = 3600 * 1000 * 1000
+
+// ReplayWindow 回放时一个时间窗口内的统计
+type ReplayWindow struct {
+	// Offset 窗口相对开始时间的偏移(us)
+	Offset   int64
+	Requests int64
+	Errors   int64
+	// Latency 窗口内成功请求的时延分布，有效位数比整体的低
+	Latency *stat.Summary
+}
+
+// Replayed 请求日志回放的结果
+type Replayed struct {
+	Report *Report
+	// Errors 按错误分类的错误数
+	Errors map[string]int64
+	// Sample 日志的采样比例，小于1时请求数和错误数按比例放大
+	Sample float64
+	// Window 时间窗口的时长(us)，0表示没有切分
+	Window  int64
+	Windows []ReplayWindow
+}
+
+// replayWindow 回放中的时间窗口
+type replayWindow struct {
+	requests int64
+	errors   int64
+	h        *hdrhistogram.Histogram
+}
+
+// Replay 把请求日志回放为压测报告，多个文件(例如多个执行器的日志)合并为一次压测
+// window大于0时按该时长(us)切分时间窗口，用于分析周期性的卡顿
+func Replay(paths []string, percentiles []float64, window int64) (*Replayed, error) {
+	readers := make([]*reqlog.Reader, 0, len(paths))
+	defer func() {
+		for _, rd := range readers {
+			_ = rd.Close()
+		}
+	}()
+	res := &Replayed{Errors: make(map[string]int64), Window: window}
+	run := &runner.RunInfo{State: runner.RunFinished, StartAt: math.MaxInt64}
+	run.Config.Percentiles = percentiles
+	percentiles = run.Config.ReportPercentiles()
+	for _, p := range paths {
+		rd, err := reqlog.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		readers = append(readers, rd)
+		h := rd.Header
+		switch {
+		case run.ID == "":
+			run.ID, run.Config.WorkerName, run.Config.Tags, res.Sample = h.RunID, h.Worker, h.Tags, h.Sample
+		case h.RunID != run.ID:
+			logger.Warning("Request log %s is of run %s, not %s", p, h.RunID, run.ID)
+		}
+		if h.Sample != res.Sample {
+			return nil, fmt.Errorf("%w: request log %s sampled %v, others %v", conf.ErrInvalidConfig, p, h.Sample, res.Sample)
+		}
+		run.Config.Workers += h.Workers
+		run.StartAt = min(run.StartAt, h.StartAt)
+	}
+	hist := hdrhistogram.New(1, maxReplayLatency, 3)
+	windows := make(map[int64]*replayWindow)
+	var total, errs int64
+	begin, end := int64(math.MaxInt64), int64(0)
+	for i, rd := range readers {
+		var r reqlog.Record
+		for {
+			err := rd.Next(&r)
+			if err == io.EOF {
+				break
+			}
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				// 没有正常结束的压测进程写入的日志会被截断，使用已经读到的请求
+				logger.Warning("Request log %s is truncated, replay %d records read", paths[i], total)
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read request log %s: %w", paths[i], err)
+			}
+			total++
+			begin, end = min(begin, r.Time), max(end, r.Time+r.Latency)
+			var w *replayWindow
+			if window > 0 {
+				idx := (r.Time - run.StartAt) / window
+				if w = windows[idx]; w == nil {
+					w = &replayWindow{h: hdrhistogram.New(1, maxReplayLatency, 2)}
+					windows[idx] = w
+				}
+				w.requests++
+			}
+			// 与压测时的统计一致，出错的请求不计入时延
+			if r.Error != "" {
+				errs++
+				res.Errors[r.Error]++
+				if w != nil {
+					w.errors++
+				}
+				continue
+			}
+			latency := min(max(r.Latency, 1), maxReplayLatency)
+			_ = hist.RecordValue(latency)
+			if w != nil {
+				_ = w.h.RecordValue(latency)
+			}
+		}
+	}
+	if total == 0 {
+		begin, end = run.StartAt, run.StartAt
+	}
+	if res.Sample <= 0 {
+		res.Sample = 1
+	}
+	scale := 1 / res.Sample
+	complete := int64(math.Round(float64(total) * scale))
+	errorTotal := int64(math.Round(float64(errs) * scale))
+	durations := end - begin
+	seconds := durations / 1000 / 1000
+	if seconds == 0 {
+		seconds = 1
+	}
+	// 与实时压测一致，汇总的 SendTotal 只有成功的请求
+	summary := (&stat.IntervalStatistic{Histogram: hist, SendTotal: complete - errorTotal, ErrorTotal: errorTotal}).LatencySummary(percentiles)
+	run.EndAt = end
+	run.Result = &runner.RunResult{
+		Complete:  complete,
+		Durations: durations,
+		ReqPerS:   complete / seconds,
+		Latency:   summary,
+	}
+	res.Report = &Report{Run: run, Pass: true}
+	if len(run.Config.Tags) > 0 {
+		res.Report.Scenario = run.Config.Tags["scenario"]
+	}
+	keys := make([]int64, 0, len(windows))
+	for k := range windows {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return keys[a] < keys[b]
+	})
+	for _, k := range keys {
+		w := windows[k]
+		ss := &stat.IntervalStatistic{Histogram: w.h}
+		rw := ReplayWindow{
+			Offset:   k * window,
+			Requests: int64(math.Round(float64(w.requests) * scale)),
+			Errors:   int64(math.Round(float64(w.errors) * scale)),
+			Latency:  ss.LatencySummary(percentiles),
+		}
+		res.Windows = append(res.Windows, rw)
+		// 时间窗口同时作为区间样本保存到报告中，供 compare 做统计检验
+		run.Result.Intervals = append(run.Result.Intervals, stat.IntervalSample{
+			Start:       (run.StartAt + rw.Offset) / 1000,
+			Durations:   window,
+			Requests:    rw.Requests,
+			Errors:      rw.Errors,
+			Mean:        w.h.Mean(),
+			Max:         w.h.Max(),
+			Percentiles: rw.Latency.Percentiles,
+		})
+	}
+	return res, nil
+}
+
+// Print 打印回放的报告、错误分类和时间窗口
+func (rs *Replayed) Print(w io.Writer) {
+	if rs.Sample < 1 {
+		_, _ = fmt.Fprintf(w, "Sampled %.2f%% of requests, counts are scaled\n", rs.Sample*100)
+	}
+	rs.Report.Print(w)
+	if len(rs.Errors) > 0 {
+		classes := make([]string, 0, len(rs.Errors))
+		for k := range rs.Errors {
+			classes = append(classes, k)
+		}
+		sort.Strings(classes)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "Errors by class:")
+		for _, c := range classes {
+			_, _ = fmt.Fprintf(tw, "  %s:\t%d\n", c, int64(math.Round(float64(rs.Errors[c])/rs.Sample)))
+		}
+		_ = tw.Flush()
+	}
+	if len(rs.Windows) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	header := []string{"offset", "requests", "rps", "errors"}
+	for _, p := range rs.Report.Run.Config.ReportPercentiles() {
+		header = append(header, stat.PercentileName(p))
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t")+"\t")
+	for _, win := range rs.Windows {
+		row := []string{
+			stat.FormatLatency(win.Offset),
+			fmt.Sprintf("%d", win.Requests),
+			fmt.Sprintf("%.0f", float64(win.Requests)/(float64(rs.Window)/1000/1000)),
+			fmt.Sprintf("%d", win.Errors),
+		}
+		for _, p := range win.Latency.Percentiles {
+			v := "-"
+			if win.Requests > win.Errors {
+				v = stat.FormatLatency(p.Value)
+			}
+			row = append(row, v)
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t")+"\t")
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,88 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"perform-cli-framework-go/src/stat"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// ReadReport 读取 json 输出写入的结果文件
+func ReadReport(path string) (*Report, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rp := &Report{}
+	if err := json.Unmarshal(b, rp); err != nil {
+		return nil, fmt.Errorf("parse report %s: %w", path, err)
+	}
+	if rp.Run == nil || rp.Run.Result == nil {
+		return nil, fmt.Errorf("report %s has no run result", path)
+	}
+	return rp, nil
+}
+
+// Print 以文本形式打印结果
+func (rp *Report) Print(w io.Writer) {
+	run := rp.Run
+	res := run.Result
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	defer func() {
+		_ = tw.Flush()
+	}()
+	if rp.Scenario != "" {
+		_, _ = fmt.Fprintf(tw, "Scenario:\t%s\n", rp.Scenario)
+	}
+	_, _ = fmt.Fprintf(tw, "Run:\t%s (%s)\n", run.ID, run.State)
+	if len(run.Config.Tags) > 0 {
+		keys := make([]string, 0, len(run.Config.Tags))
+		for k := range run.Config.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := make([]string, 0, len(keys))
+		for _, k := range keys {
+			tags = append(tags, k+"="+run.Config.Tags[k])
+		}
+		_, _ = fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(tags, ","))
+	}
+	_, _ = fmt.Fprintf(tw, "Worker:\t%s x %d\n", run.Config.WorkerName, run.Config.Workers)
+	_, _ = fmt.Fprintf(tw, "Duration:\t%d s\n", res.Durations/1000/1000)
+	_, _ = fmt.Fprintf(tw, "Requests:\t%d\n", res.Complete)
+	_, _ = fmt.Fprintf(tw, "Requests/sec:\t%d\n", res.ReqPerS)
+	if l := res.Latency; l != nil {
+		errRate, _ := MetricValue("error_rate", res)
+		_, _ = fmt.Fprintf(tw, "Errors:\t%d (%.2f%%)\n", l.ErrorTotal, errRate*100)
+		_, _ = fmt.Fprintf(tw, "Latency:\tmin %s, mean %s, stddev %s, max %s\n", stat.FormatLatency(l.Min),
+			stat.FormatLatency(int64(l.Mean)), stat.FormatLatency(int64(l.StdDev)), stat.FormatLatency(l.Max))
+		for _, p := range l.Percentiles {
+			_, _ = fmt.Fprintf(tw, "  %s:\t%s\n", strings.ToUpper(stat.PercentileName(p.Percentile)), stat.FormatLatency(p.Value))
+		}
+		if len(l.Phases) > 0 {
+			_, _ = fmt.Fprintf(tw, "Phases:\n")
+		}
+		for i := range l.Phases {
+			name, value, _ := strings.Cut(l.Phases[i].String(), ": ")
+			_, _ = fmt.Fprintf(tw, "  %s:\t%s\n", name, value)
+		}
+		if len(l.Metrics) > 0 {
+			_, _ = fmt.Fprintf(tw, "Metrics:\n")
+		}
+		for i := range l.Metrics {
+			name, value, _ := strings.Cut(l.Metrics[i].String(), ": ")
+			_, _ = fmt.Fprintf(tw, "  %s (%s):\t%s\n", name, l.Metrics[i].Kind, value)
+		}
+	}
+	for _, t := range rp.Thresholds {
+		verdict := "pass"
+		if !t.Pass {
+			verdict = "FAIL"
+		}
+		_, _ = fmt.Fprintf(tw, "Threshold %s:\t%s (%v)\n", t.String(), verdict, t.Actual)
+	}
+}
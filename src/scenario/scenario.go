@@ -0,0 +1,282 @@
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/stat"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Seconds 以秒为单位的时长，可以写成整数秒或 30s、10m 这样的字符串
+type Seconds int64
+
+// parseSeconds 与命令行的 secondsFlag 一致，不是整秒或为负数时报错，避免 500ms 被当作 0 而使用默认值
+func parseSeconds(s string) (Seconds, error) {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if v < 0 {
+			return 0, fmt.Errorf("duration %q must not be negative", s)
+		}
+		return Seconds(v), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q, want e.g. 30 or 30s, 10m", s)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration %q must not be negative", s)
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("duration %q must be whole seconds", s)
+	}
+	return Seconds(d / time.Second), nil
+}
+
+func (s *Seconds) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch t := v.(type) {
+	case float64:
+		if t < 0 || t != math.Trunc(t) {
+			return fmt.Errorf("duration %v must be whole non-negative seconds", t)
+		}
+		*s = Seconds(t)
+		return nil
+	case string:
+		d, err := parseSeconds(t)
+		*s = d
+		return err
+	}
+	return fmt.Errorf("invalid duration %s", string(b))
+}
+
+func (s *Seconds) UnmarshalYAML(node *yaml.Node) error {
+	d, err := parseSeconds(node.Value)
+	*s = d
+	return err
+}
+
+// Stage 压测的一个阶段
+type Stage struct {
+	Duration Seconds `json:"duration" yaml:"duration"`
+	Rate     int64   `json:"rate" yaml:"rate"`
+}
+
+// Load 发压模型
+type Load struct {
+	Workers      int64   `json:"workers" yaml:"workers"`
+	Duration     Seconds `json:"duration" yaml:"duration"`
+	Timeout      Seconds `json:"timeout" yaml:"timeout"`
+	Rate         int64   `json:"rate" yaml:"rate"`
+	Nums         int64   `json:"nums" yaml:"nums"`
+	DrainTimeout Seconds `json:"drain_timeout" yaml:"drain_timeout"`
+	StatInterval Seconds `json:"stat_interval" yaml:"stat_interval"`
+	Stages       []Stage `json:"stages" yaml:"stages"`
+}
+
+// Percentiles 分位数列表，可以写数字，也可以写 p99.9、max
+type Percentiles []float64
+
+func (ps *Percentiles) set(values []interface{}) error {
+	res := make([]float64, 0, len(values))
+	for _, v := range values {
+		p, err := stat.ParsePercentile(fmt.Sprint(v))
+		if err != nil {
+			return err
+		}
+		res = append(res, p)
+	}
+	*ps = res
+	return nil
+}
+
+func (ps *Percentiles) UnmarshalJSON(b []byte) error {
+	var values []interface{}
+	if err := json.Unmarshal(b, &values); err != nil {
+		return err
+	}
+	return ps.set(values)
+}
+
+func (ps *Percentiles) UnmarshalYAML(node *yaml.Node) error {
+	var values []interface{}
+	if err := node.Decode(&values); err != nil {
+		return err
+	}
+	return ps.set(values)
+}
+
+// Reporting 控制台输出的周期、控制台和报告中输出的分位数以及区间直方图日志
+type Reporting struct {
+	Interval    Seconds     `json:"interval" yaml:"interval"`
+	Percentiles Percentiles `json:"percentiles" yaml:"percentiles"`
+	// Hlog 区间直方图写入的 HdrHistogram 日志路径
+	Hlog string `json:"hlog" yaml:"hlog"`
+	// Sinks 区间统计和最终汇总推送的目标
+	Sinks []string `json:"sinks" yaml:"sinks"`
+}
+
+// Worker 使用的工作器和它的配置，Config 可以写成对象，也可以写成json字符串
+type Worker struct {
+	Name        string      `json:"name" yaml:"name"`
+	Config      interface{} `json:"config" yaml:"config"`
+	PrintErrors bool        `json:"print_errors" yaml:"print_errors"`
+}
+
+// Scenario 一个完整的压测场景
+type Scenario struct {
+	Name       string               `json:"name" yaml:"name"`
+	Tags       map[string]string    `json:"tags" yaml:"tags"`
+	Load       Load                 `json:"load" yaml:"load"`
+	Report     Reporting            `json:"report" yaml:"report"`
+	Worker     Worker               `json:"worker" yaml:"worker"`
+	Feeders    []conf.FeederConf    `json:"feeders" yaml:"feeders"`
+	Trace      *conf.TraceConf      `json:"trace" yaml:"trace"`
+	RequestLog *conf.RequestLogConf `json:"request_log" yaml:"request_log"`
+	Thresholds []Threshold          `json:"thresholds" yaml:"thresholds"`
+	Outputs    []Output             `json:"outputs" yaml:"outputs"`
+}
+
+// LoadFile 读取场景文件，.json 按json解析，其他按yaml解析，解析前先替换环境变量
+func LoadFile(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	content := expandEnv(string(b))
+	s := &Scenario{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal([]byte(content), s)
+	} else {
+		err = yaml.Unmarshal([]byte(content), s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse scenario %s: %w", path, err)
+	}
+	// 数据源的相对路径相对于场景文件
+	for i := range s.Feeders {
+		if s.Feeders[i].File != "" && !filepath.IsAbs(s.Feeders[i].File) {
+			s.Feeders[i].File = filepath.Join(filepath.Dir(path), s.Feeders[i].File)
+		}
+	}
+	return s, nil
+}
+
+// envPattern 匹配 $$、${VAR} 和 ${VAR:-default}
+var envPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// expandEnv 替换 ${VAR} 和 ${VAR:-default}，$$ 表示 $ 本身，其他的 $ 保持不变，例如正则中的 $1、schema 中的 $ref
+func expandEnv(content string) string {
+	return envPattern.ReplaceAllStringFunc(content, func(m string) string {
+		if m == "$$" {
+			return "$"
+		}
+		sub := envPattern.FindStringSubmatch(m)
+		if v, ok := os.LookupEnv(sub[1]); ok && v != "" {
+			return v
+		}
+		// 没有默认值时与 shell 一样替换为空
+		return sub[2]
+	})
+}
+
+// Config 把场景转换成压测配置，未设置的字段使用命令行的默认值
+func (s *Scenario) Config(defaults conf.BenchConfig) (conf.BenchConfig, error) {
+	cfg := defaults
+	l := s.Load
+	setInt(&cfg.Workers, l.Workers)
+	setInt(&cfg.Duration, int64(l.Duration))
+	setInt(&cfg.Timeout, int64(l.Timeout))
+	setInt(&cfg.Rate, l.Rate)
+	setInt(&cfg.Nums, l.Nums)
+	setInt(&cfg.DrainTimeout, int64(l.DrainTimeout))
+	setInt(&cfg.StatInterval, int64(l.StatInterval))
+	setInt(&cfg.ReportInterval, int64(s.Report.Interval))
+	if s.Report.Hlog != "" {
+		cfg.Hlog = s.Report.Hlog
+	}
+	if len(s.Report.Percentiles) > 0 {
+		cfg.Percentiles = s.Report.Percentiles
+	}
+	if len(s.Report.Sinks) > 0 {
+		cfg.Sinks = s.Report.Sinks
+	}
+	for _, st := range l.Stages {
+		cfg.Stages = append(cfg.Stages, conf.Stage{Duration: int64(st.Duration), Rate: st.Rate})
+	}
+	if s.Worker.Name != "" {
+		cfg.WorkerName = s.Worker.Name
+	}
+	cfg.PError = cfg.PError || s.Worker.PrintErrors
+	switch wc := s.Worker.Config.(type) {
+	case nil:
+	case string:
+		cfg.WorkerConfig = wc
+	default:
+		js, err := json.Marshal(wc)
+		if err != nil {
+			return cfg, fmt.Errorf("%w: worker config: %v", conf.ErrInvalidConfig, err)
+		}
+		cfg.WorkerConfig = string(js)
+	}
+	cfg.Feeders = s.Feeders
+	cfg.Tags = s.Tags
+	cfg.Trace = s.Trace
+	cfg.RequestLog = s.RequestLog
+	if s.Name != "" {
+		if cfg.Tags == nil {
+			cfg.Tags = make(map[string]string)
+		}
+		if _, ok := cfg.Tags["scenario"]; !ok {
+			cfg.Tags["scenario"] = s.Name
+		}
+	}
+	for i := range s.Thresholds {
+		if err := s.Thresholds[i].check(); err != nil {
+			return cfg, err
+		}
+	}
+	for i := range s.Outputs {
+		if err := s.Outputs[i].check(); err != nil {
+			return cfg, err
+		}
+	}
+	s.AddThresholdPercentiles(&cfg)
+	return cfg, nil
+}
+
+// AddThresholdPercentiles 把条件中使用而没有统计的分位数加入 cfg.Percentiles，否则这些条件总是不满足
+// 命令行覆盖 -percentiles 后需要再调用一次
+func (s *Scenario) AddThresholdPercentiles(cfg *conf.BenchConfig) {
+	ps := cfg.ReportPercentiles()
+	added := false
+	for i := range s.Thresholds {
+		p, ok := s.Thresholds[i].percentile()
+		if ok && !slices.Contains(ps, p) {
+			// 不修改默认的分位数列表
+			ps = append(slices.Clip(ps), p)
+			added = true
+		}
+	}
+	if added {
+		slices.Sort(ps)
+		cfg.Percentiles = ps
+	}
+}
+
+func setInt(dst *int64, v int64) {
+	if v != 0 {
+		*dst = v
+	}
+}
@@ -0,0 +1,198 @@
+package scenario
+
+import (
+	"fmt"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"strconv"
+	"strings"
+)
+
+// Threshold 压测结果需要满足的条件，例如 metric: p99, op: "<", value: 20000
+// 支持的指标：requests、rps、errors、error_rate、min、max、mean、stddev 以及 p50 这样的分位数，时延单位为us
+// 自定义指标写为 metrics.<name>，直方图为 metrics.<name>.count、metrics.<name>.p99 等
+// 请求各阶段的耗时写为 phase.<phase>.<field>，例如 phase.ttfb.p99、phase.dns.mean
+type Threshold struct {
+	Metric string  `json:"metric" yaml:"metric"`
+	Op     string  `json:"op" yaml:"op"`
+	Value  float64 `json:"value" yaml:"value"`
+}
+
+// ThresholdResult 单个条件的检查结果
+type ThresholdResult struct {
+	Threshold
+	Actual float64 `json:"actual"`
+	Pass   bool    `json:"pass"`
+}
+
+func (t *Threshold) String() string {
+	return fmt.Sprintf("%s %s %v", t.Metric, t.Op, t.Value)
+}
+
+func (t *Threshold) check() error {
+	switch t.Op {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		return fmt.Errorf("%w: threshold %s: unknown op %q", conf.ErrInvalidConfig, t.Metric, t.Op)
+	}
+	switch t.Metric {
+	case "requests", "rps", "errors", "error_rate", "min", "max", "mean", "stddev":
+		return nil
+	}
+	if strings.HasPrefix(t.Metric, metricsPrefix) && len(t.Metric) > len(metricsPrefix) {
+		return nil
+	}
+	if rest, ok := strings.CutPrefix(t.Metric, phasePrefix); ok {
+		if phase, field, ok := strings.Cut(rest, "."); ok && phase != "" && field != "" {
+			return nil
+		}
+	}
+	if p, err := strconv.ParseFloat(strings.TrimPrefix(t.Metric, "p"), 64); err == nil && strings.HasPrefix(t.Metric, "p") && p > 0 && p <= 100 {
+		return nil
+	}
+	return fmt.Errorf("%w: threshold: unknown metric %s", conf.ErrInvalidConfig, t.Metric)
+}
+
+// percentile 条件使用的分位数，例如 p99.9、phase.ttfb.p99、metrics.size.p95，不是分位数时返回false
+func (t *Threshold) percentile() (float64, bool) {
+	field := t.Metric
+	if strings.HasPrefix(field, metricsPrefix) || strings.HasPrefix(field, phasePrefix) {
+		i := strings.LastIndex(field, ".p")
+		if i < 0 {
+			return 0, false
+		}
+		field = field[i+1:]
+	}
+	if !strings.HasPrefix(field, "p") {
+		return 0, false
+	}
+	p, err := stat.ParsePercentile(field)
+	return p, err == nil
+}
+
+func compare(actual float64, op string, v float64) bool {
+	switch op {
+	case "<":
+		return actual < v
+	case "<=":
+		return actual <= v
+	case ">":
+		return actual > v
+	case ">=":
+		return actual >= v
+	case "==":
+		return actual == v
+	case "!=":
+		return actual != v
+	}
+	return false
+}
+
+// MetricValue 从压测结果中取出指标的值
+func MetricValue(metric string, r *runner.RunResult) (float64, error) {
+	l := r.Latency
+	switch metric {
+	case "requests":
+		return float64(r.Complete), nil
+	case "rps":
+		return float64(r.ReqPerS), nil
+	}
+	if l == nil {
+		l = &stat.Summary{}
+	}
+	switch metric {
+	case "errors":
+		return float64(l.ErrorTotal), nil
+	case "error_rate":
+		// SendTotal 只有成功的请求，错误率按全部请求计算
+		total := l.SendTotal + l.ErrorTotal
+		if total == 0 {
+			return 0, nil
+		}
+		return float64(l.ErrorTotal) / float64(total), nil
+	case "min":
+		return float64(l.Min), nil
+	case "max":
+		return float64(l.Max), nil
+	case "mean":
+		return l.Mean, nil
+	case "stddev":
+		return l.StdDev, nil
+	}
+	if strings.HasPrefix(metric, metricsPrefix) {
+		return customMetricValue(strings.TrimPrefix(metric, metricsPrefix), l.Metrics)
+	}
+	if strings.HasPrefix(metric, phasePrefix) {
+		return phaseValue(strings.TrimPrefix(metric, phasePrefix), l.Phases)
+	}
+	if strings.HasPrefix(metric, "p") {
+		p, err := strconv.ParseFloat(metric[1:], 64)
+		if err != nil {
+			return 0, fmt.Errorf("unknown metric %s", metric)
+		}
+		for _, v := range l.Percentiles {
+			if v.Percentile == p {
+				return float64(v.Value), nil
+			}
+		}
+		return 0, fmt.Errorf("percentile %v not collected", p)
+	}
+	return 0, fmt.Errorf("unknown metric %s", metric)
+}
+
+// metricsPrefix 自定义指标在条件中的前缀
+const metricsPrefix = "metrics."
+
+// customMetricValue 取出自定义指标的值，name 为指标名称或直方图的 <name>.<field>
+func customMetricValue(name string, ms []stat.MetricSummary) (float64, error) {
+	for i := range ms {
+		field, ok := "", name == ms[i].Name
+		if !ok && strings.HasPrefix(name, ms[i].Name+".") {
+			field, ok = name[len(ms[i].Name)+1:], true
+		}
+		if !ok {
+			continue
+		}
+		if v, ok := ms[i].Field(field); ok {
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("custom metric %s not recorded", name)
+}
+
+// phasePrefix 请求阶段耗时在条件中的前缀
+const phasePrefix = "phase."
+
+// phaseValue 取出阶段耗时的值，name 为 <phase>.<field>
+func phaseValue(name string, ps []stat.PhaseSummary) (float64, error) {
+	phase, field, _ := strings.Cut(name, ".")
+	for i := range ps {
+		if ps[i].Phase != phase {
+			continue
+		}
+		if v, ok := ps[i].Field(field); ok {
+			return v, nil
+		}
+		return 0, fmt.Errorf("phase %s has no %s", phase, field)
+	}
+	return 0, fmt.Errorf("phase %s not recorded", phase)
+}
+
+// Evaluate 检查压测结果是否满足所有条件，无法计算的指标视为不满足
+func Evaluate(thresholds []Threshold, r *runner.RunResult) ([]ThresholdResult, bool) {
+	res := make([]ThresholdResult, 0, len(thresholds))
+	ok := true
+	for _, t := range thresholds {
+		tr := ThresholdResult{Threshold: t}
+		if r != nil {
+			if v, err := MetricValue(t.Metric, r); err == nil {
+				tr.Actual = v
+				tr.Pass = compare(v, t.Op, t.Value)
+			}
+		}
+		ok = ok && tr.Pass
+		res = append(res, tr)
+	}
+	return res, ok
+}
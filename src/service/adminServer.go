@@ -0,0 +1,224 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/pprof"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// adminResponse 管理接口统一的返回格式，与控制器的http接口一致
+type adminResponse struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// RuntimeStats 执行器进程的运行时状态
+type RuntimeStats struct {
+	Version    string  `json:"version"`
+	GoVersion  string  `json:"go_version"`
+	Uptime     float64 `json:"uptime_s"`
+	CPU        int     `json:"cpu"`
+	GOMAXPROCS int     `json:"gomaxprocs"`
+	Goroutines int     `json:"goroutines"`
+	HeapAlloc  uint64  `json:"heap_alloc"`
+	HeapSys    uint64  `json:"heap_sys"`
+	HeapInuse  uint64  `json:"heap_inuse"`
+	HeapObjs   uint64  `json:"heap_objects"`
+	TotalAlloc uint64  `json:"total_alloc"`
+	Sys        uint64  `json:"sys"`
+	NumGC      uint32  `json:"num_gc"`
+	GCPauseNs  uint64  `json:"gc_pause_total_ns"`
+	LastGC     int64   `json:"last_gc_unix_ms"`
+	// Generator 最近一个统计区间的压测机资源使用
+	Generator *stat.GeneratorStats `json:"generator,omitempty"`
+}
+
+// RunStatus 执行器当前的压测状态
+type RunStatus struct {
+	Running       bool                    `json:"running"`
+	Paused        bool                    `json:"paused"`
+	Rate          int64                   `json:"rate"`
+	ActiveWorkers int64                   `json:"active_workers"`
+	InFlight      int64                   `json:"in_flight"`
+	Run           *runner.RunInfo         `json:"run,omitempty"`
+	Stats         *stat.IntervalStatistic `json:"stats,omitempty"`
+}
+
+// EffectiveConfig 执行器启动时生效的配置以及当前压测的配置
+type EffectiveConfig struct {
+	Executor conf.BenchConfig  `json:"executor"`
+	Grpc     conf.GrpcConf     `json:"grpc"`
+	Run      *conf.BenchConfig `json:"run,omitempty"`
+}
+
+type admin struct {
+	r     *runner.BenchMarkRunner
+	cfg   conf.BenchConfig
+	start time.Time
+}
+
+func writeAdminJSON(w http.ResponseWriter, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&adminResponse{Code: 0, Message: "success", Data: data}); err != nil {
+		logger.Error("Write response err: %v", err)
+	}
+}
+
+// handleRuntime 返回进程的运行时状态
+func (a *admin) handleRuntime(w http.ResponseWriter, r *http.Request) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	rs := &RuntimeStats{
+		Version:    utils.Version,
+		GoVersion:  runtime.Version(),
+		Uptime:     time.Since(a.start).Seconds(),
+		CPU:        runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  ms.HeapAlloc,
+		HeapSys:    ms.HeapSys,
+		HeapInuse:  ms.HeapInuse,
+		HeapObjs:   ms.HeapObjects,
+		TotalAlloc: ms.TotalAlloc,
+		Sys:        ms.Sys,
+		NumGC:      ms.NumGC,
+		GCPauseNs:  ms.PauseTotalNs,
+		LastGC:     int64(ms.LastGC / 1000 / 1000),
+	}
+	if ss := a.r.CachedStatistics(); ss != nil {
+		rs.Generator = ss.Generator
+	}
+	writeAdminJSON(w, rs)
+}
+
+// redacted 替换敏感配置值的占位符
+const redacted = "***"
+
+// sensitiveKeys 名称中包含这些词的配置项按敏感信息处理
+var sensitiveKeys = []string{"authorization", "password", "passwd", "secret", "token", "apikey", "api_key", "cookie"}
+
+func sensitiveKey(k string) bool {
+	k = strings.ToLower(k)
+	for _, s := range sensitiveKeys {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue 隐去 headers 下的所有值以及敏感配置项的值
+func redactValue(v interface{}, hide bool) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = redactValue(e, hide || sensitiveKey(k) || strings.EqualFold(k, "headers"))
+		}
+		return t
+	case []interface{}:
+		for i, e := range t {
+			t[i] = redactValue(e, hide)
+		}
+		return t
+	case nil:
+		return nil
+	}
+	if hide {
+		return redacted
+	}
+	return v
+}
+
+// redactConfig 返回隐去工作器配置中请求头等敏感信息的配置，避免通过管理接口泄露
+func redactConfig(c conf.BenchConfig) conf.BenchConfig {
+	if c.WorkerConfig == "" {
+		return c
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(c.WorkerConfig), &v); err != nil {
+		c.WorkerConfig = redacted
+		return c
+	}
+	js, err := json.Marshal(redactValue(v, false))
+	if err != nil {
+		c.WorkerConfig = redacted
+		return c
+	}
+	c.WorkerConfig = string(js)
+	return c
+}
+
+// redactRun 返回隐去工作器配置中敏感信息的压测快照
+func redactRun(run *runner.RunInfo) *runner.RunInfo {
+	if run == nil {
+		return nil
+	}
+	cp := *run
+	cp.Config = redactConfig(run.Config)
+	return &cp
+}
+
+// handleConfig 返回生效的配置，工作器配置中的请求头和密码等敏感信息会被隐去
+func (a *admin) handleConfig(w http.ResponseWriter, r *http.Request) {
+	ec := &EffectiveConfig{Executor: redactConfig(a.cfg), Grpc: a.cfg.GrpcCfg}
+	if run := a.r.CurrentRun(); run != nil {
+		rc := redactConfig(run.Config)
+		ec.Run = &rc
+	}
+	writeAdminJSON(w, ec)
+}
+
+// handleRun 返回当前的压测状态
+func (a *admin) handleRun(w http.ResponseWriter, r *http.Request) {
+	writeAdminJSON(w, &RunStatus{
+		Running:       a.r.IsRunning(),
+		Paused:        a.r.IsPaused(),
+		Rate:          a.r.Rate(),
+		ActiveWorkers: a.r.ActiveWorkers(),
+		InFlight:      a.r.InFlight(),
+		Run:           redactRun(a.r.CurrentRun()),
+		Stats:         a.r.CachedStatistics(),
+	})
+}
+
+// AdminHandler 管理接口的http路由，包括 net/http/pprof 以及运行时状态、配置和压测状态
+func AdminHandler(r *runner.BenchMarkRunner, cfg conf.BenchConfig) http.Handler {
+	a := &admin{r: r, cfg: cfg, start: time.Now()}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/runtime", a.handleRuntime)
+	mux.HandleFunc("/debug/config", a.handleConfig)
+	mux.HandleFunc("/debug/run", a.handleRun)
+	return mux
+}
+
+// NewAdminServer 创建管理接口的http服务，由调用方启动和关闭
+func NewAdminServer(addr string, r *runner.BenchMarkRunner, cfg conf.BenchConfig) *http.Server {
+	return &http.Server{Addr: addr, Handler: AdminHandler(r, cfg)}
+}
+
+// StartAdminServer 启动管理接口的http服务，阻塞直到服务停止
+func StartAdminServer(srv *http.Server) error {
+	logger.Info("Starting admin server on %s", srv.Addr)
+	err := srv.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -3,10 +3,15 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"perform-cli-framework-go/src/conf"
 	"perform-cli-framework-go/src/logger"
 	"perform-cli-framework-go/src/runner"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"perform-cli-framework-go/src/worker"
+	"time"
 
 	"google.golang.org/grpc"
 
@@ -24,18 +29,28 @@ type server struct {
 // StartPerform 实现 PerformService 的 StartPerform 方法
 func (s *server) StartPerform(ctx context.Context, req *perform_pb.StartMessage) (*perform_pb.CmRespMessage, error) {
 	// 处理 StartPerform 请求
+	recvTime := utils.GetTimeUs()
 	jsonBodyStr := req.GetJson()
 	benchConfig := conf.BenchConfig{}
 	err := json.Unmarshal(jsonBodyStr, &benchConfig)
 	if err != nil {
-		return &perform_pb.CmRespMessage{Code: -1, Message: []byte(fmt.Sprintf("Failed parse json str: %s",
+		return &perform_pb.CmRespMessage{Code: int32(perform_pb.ErrCode_ERR_INVALID_CONFIG), Message: []byte(fmt.Sprintf("Failed parse json str: %s",
 			jsonBodyStr))}, nil
 	}
 	// 这里要标记下时grpc服务启动的
 	benchConfig.GrpcCfg.Enable = true
 	logger.Info("Start benchmark with conf: %v", benchConfig)
-	s.Runner.StartAsync(benchConfig)
-	return &perform_pb.CmRespMessage{Code: 0, Message: []byte("success")}, nil
+	var offset int64
+	if req.GetControllerTime() > 0 {
+		offset = recvTime - req.GetControllerTime()
+	}
+	runID, err := s.Runner.StartAsync(benchConfig)
+	if err != nil {
+		logger.Error("Start benchmark err: %v", err)
+		return &perform_pb.CmRespMessage{Code: int32(errCode(err)), Message: []byte(err.Error()), RunId: runID, ClockOffset: offset}, nil
+	}
+	// 返回时全局前置已经完成，指定了开始时间的压测处于就绪状态
+	return &perform_pb.CmRespMessage{Code: 0, Message: []byte("success"), RunId: runID, ClockOffset: offset}, nil
 }
 
 // StopPerform 实现 PerformService 的 StopPerform 方法
@@ -53,6 +68,103 @@ func (s *server) CollectStats(ctx context.Context, req *perform_pb.EmptyMessage)
 	if statistic == nil {
 		return &perform_pb.PerformMessage{Code: -1}, nil
 	}
+	return &perform_pb.PerformMessage{Code: 0, Stats: toPerformStats(statistic, s.Runner.Percentiles())}, nil
+}
+
+// StreamStats 实现 PerformService 的 StreamStats 方法，按周期推送区间统计直到客户端断开
+func (s *server) StreamStats(req *perform_pb.StatsStreamRequest, stream perform_pb.PerformService_StreamStatsServer) error {
+	every := 1
+	if base := int64(s.Runner.StatInterval() / time.Second); base > 0 && req.GetInterval() > base {
+		every = int(req.GetInterval() / base)
+	}
+	sub := s.Runner.SubscribeStatistics(every)
+	defer s.Runner.Unsubscribe(sub)
+	logger.Info("Stats stream subscribed, every %d intervals", every)
+	for {
+		select {
+		case <-stream.Context().Done():
+			logger.Info("Stats stream closed")
+			return nil
+		case statistic, ok := <-sub.C:
+			if !ok {
+				return nil
+			}
+			err := stream.Send(&perform_pb.PerformMessage{Code: 0, Stats: toPerformStats(statistic, s.Runner.Percentiles())})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SyncClock 实现 PerformService 的 SyncClock 方法，返回执行器当前的时间
+func (s *server) SyncClock(ctx context.Context, req *perform_pb.ClockMessage) (*perform_pb.ClockMessage, error) {
+	return &perform_pb.ClockMessage{Time: utils.GetTimeUs()}, nil
+}
+
+// KeepAlive 实现 PerformService 的 KeepAlive 方法
+func (s *server) KeepAlive(ctx context.Context, req *perform_pb.EmptyMessage) (*perform_pb.ExecutorStatus, error) {
+	// 处理 KeepAlive 请求
+	run := s.Runner.CurrentRun()
+	if run == nil {
+		return &perform_pb.ExecutorStatus{Status: perform_pb.Status_STATUS_IDLE}, nil
+	}
+	var status perform_pb.Status
+	switch {
+	case run.State == runner.RunSetupFailed || run.State == runner.RunFailed:
+		status = perform_pb.Status_STATUS_ERROR
+	case run.State.Terminal():
+		status = perform_pb.Status_STATUS_IDLE
+	case s.Runner.IsPaused():
+		status = perform_pb.Status_STATUS_PAUSED
+	default:
+		status = perform_pb.Status_STATUS_RUNNING
+	}
+	return &perform_pb.ExecutorStatus{
+		Status: status,
+		RunId:  run.ID,
+		State:  run.State.String(),
+		Reason: run.Reason,
+	}, nil
+}
+
+// GetRun 实现 PerformService 的 GetRun 方法
+func (s *server) GetRun(ctx context.Context, req *perform_pb.RunQuery) (*perform_pb.RunMessage, error) {
+	run := s.Runner.GetRun(req.GetRunId())
+	if run == nil {
+		return &perform_pb.RunMessage{Code: -1, Message: []byte(fmt.Sprintf("run %s not found", req.GetRunId()))}, nil
+	}
+	return &perform_pb.RunMessage{Code: 0, Run: toRunInfo(run)}, nil
+}
+
+// ListRuns 实现 PerformService 的 ListRuns 方法
+func (s *server) ListRuns(ctx context.Context, req *perform_pb.EmptyMessage) (*perform_pb.RunListMessage, error) {
+	runs := make([]*perform_pb.RunInfo, 0)
+	for _, run := range s.Runner.ListRuns() {
+		runs = append(runs, toRunInfo(run))
+	}
+	return &perform_pb.RunListMessage{Code: 0, Runs: runs}, nil
+}
+
+// PausePerform 实现 PerformService 的 PausePerform 方法
+func (s *server) PausePerform(ctx context.Context, req *perform_pb.EmptyMessage) (*perform_pb.CmRespMessage, error) {
+	logger.Info("Received PausePerform request")
+	if err := s.Runner.Pause(); err != nil {
+		return &perform_pb.CmRespMessage{Code: int32(errCode(err)), Message: []byte(err.Error())}, nil
+	}
+	return &perform_pb.CmRespMessage{Code: 0, Message: []byte("success")}, nil
+}
+
+// ResumePerform 实现 PerformService 的 ResumePerform 方法
+func (s *server) ResumePerform(ctx context.Context, req *perform_pb.EmptyMessage) (*perform_pb.CmRespMessage, error) {
+	logger.Info("Received ResumePerform request")
+	if err := s.Runner.Resume(); err != nil {
+		return &perform_pb.CmRespMessage{Code: int32(errCode(err)), Message: []byte(err.Error())}, nil
+	}
+	return &perform_pb.CmRespMessage{Code: 0, Message: []byte("success")}, nil
+}
+
+func toPerformStats(statistic *stat.IntervalStatistic, percentiles []float64) *perform_pb.PerformStats {
 	records := make([]*perform_pb.Record, 0)
 	for _, v := range statistic.Records {
 		records = append(records, &perform_pb.Record{
@@ -61,24 +173,150 @@ func (s *server) CollectStats(ctx context.Context, req *perform_pb.EmptyMessage)
 		})
 	}
 	stats := &perform_pb.PerformStats{
-		Duration:  statistic.Durations,
-		ErrCount:  statistic.ErrorTotal,
-		SendCount: statistic.SendTotal,
-		SendBytes: statistic.SendBytes,
-		RecvBytes: statistic.RecvBytes,
-		Latency:   records,
-		ErrMsgs:   make([][]byte, 0),
+		Duration:       statistic.Durations,
+		ErrCount:       statistic.ErrorTotal,
+		SendCount:      statistic.SendTotal,
+		SendBytes:      statistic.SendBytes,
+		RecvBytes:      statistic.RecvBytes,
+		Latency:        records,
+		ErrMsgs:        make([][]byte, 0),
+		PausedDuration: statistic.PausedDurations,
+		Paused:         statistic.Paused,
 	}
-	return &perform_pb.PerformMessage{Code: 0, Stats: stats}, nil
+	if g := statistic.Generator; g != nil {
+		stats.Generator = &perform_pb.GeneratorStats{
+			Cpu:             g.CPU,
+			Cores:           g.Cores,
+			Goroutines:      g.Goroutines,
+			GcCount:         g.GCCount,
+			GcPause:         g.GCPause,
+			GcPauseMax:      g.GCPauseMax,
+			SchedLatencyP99: g.SchedLatencyP99,
+			SchedLatencyMax: g.SchedLatencyMax,
+			TargetRate:      g.TargetRate,
+			LimiterLag:      g.LimiterLag,
+			Saturated:       g.Saturated,
+		}
+	}
+	stats.Metrics = toMetrics(statistic.Metrics)
+	for k, h := range statistic.Phases {
+		encoded, err := stat.EncodeHistogram(h)
+		if err != nil {
+			logger.Error("Encode histogram of phase %s err: %v", k, err)
+			continue
+		}
+		stats.Phases = append(stats.Phases, &perform_pb.Phase{Name: k, Histogram: encoded})
+	}
+	if h := statistic.Histogram; h != nil {
+		encoded, err := stat.EncodeHistogram(h)
+		if err != nil {
+			logger.Error("Encode histogram err: %v", err)
+			return stats
+		}
+		stats.Histogram = encoded
+		stats.HistLowest = h.LowestTrackableValue()
+		stats.HistHighest = h.HighestTrackableValue()
+		stats.HistSigFigs = int32(h.SignificantFigures())
+		for _, p := range percentiles {
+			stats.Percentiles = append(stats.Percentiles, &perform_pb.Percentile{Percentile: p, Value: h.ValueAtPercentile(p)})
+		}
+	}
+	return stats
 }
 
-// KeepAlive 实现 PerformService 的 KeepAlive 方法
-func (s *server) KeepAlive(ctx context.Context, req *perform_pb.EmptyMessage) (*perform_pb.ExecutorStatus, error) {
-	// 处理 KeepAlive 请求
-	if s.Runner.IsRunning() {
-		return &perform_pb.ExecutorStatus{Status: perform_pb.Status_STATUS_RUNNING}, nil
+// toMetrics 转换区间内的自定义指标，直方图编码后传输，由控制器合并
+func toMetrics(m *stat.Metrics) []*perform_pb.Metric {
+	if m == nil {
+		return nil
+	}
+	res := make([]*perform_pb.Metric, 0, len(m.Counters)+len(m.Gauges)+len(m.Histograms))
+	for k, v := range m.Counters {
+		res = append(res, &perform_pb.Metric{Name: k, Kind: stat.MetricCounter, Value: float64(v)})
+	}
+	for k, v := range m.Gauges {
+		res = append(res, &perform_pb.Metric{Name: k, Kind: stat.MetricGauge, Value: v})
+	}
+	for k, h := range m.Histograms {
+		encoded, err := stat.EncodeHistogram(h)
+		if err != nil {
+			logger.Error("Encode histogram of metric %s err: %v", k, err)
+			continue
+		}
+		res = append(res, &perform_pb.Metric{Name: k, Kind: stat.MetricHistogram, Value: float64(h.TotalCount()), Histogram: encoded})
+	}
+	return res
+}
+
+// errCode 将runner返回的错误转换成CmRespMessage的错误码
+func errCode(err error) perform_pb.ErrCode {
+	switch {
+	case err == nil:
+		return perform_pb.ErrCode_ERR_OK
+	case errors.Is(err, conf.ErrInvalidConfig):
+		return perform_pb.ErrCode_ERR_INVALID_CONFIG
+	case errors.Is(err, worker.ErrUnknownWorker):
+		return perform_pb.ErrCode_ERR_UNKNOWN_WORKER
+	case errors.Is(err, runner.ErrRunActive):
+		return perform_pb.ErrCode_ERR_RUN_ACTIVE
+	case errors.Is(err, runner.ErrSetupFailed):
+		return perform_pb.ErrCode_ERR_SETUP_FAILED
+	case errors.Is(err, runner.ErrNotRunning), errors.Is(err, runner.ErrAlreadyPaused), errors.Is(err, runner.ErrNotPaused):
+		return perform_pb.ErrCode_ERR_INVALID_STATE
+	default:
+		return perform_pb.ErrCode_ERR_INTERNAL
+	}
+}
+
+func toRunInfo(run *runner.RunInfo) *perform_pb.RunInfo {
+	js, _ := json.Marshal(run.Config)
+	info := &perform_pb.RunInfo{
+		RunId:     run.ID,
+		State:     run.State.String(),
+		Reason:    run.Reason,
+		StartTime: run.StartAt,
+		EndTime:   run.EndAt,
+		Config:    js,
+	}
+	res := run.Result
+	if res == nil {
+		return info
+	}
+	summary := &perform_pb.RunSummary{
+		Complete:  res.Complete,
+		Duration:  res.Durations,
+		ReqPerSec: res.ReqPerS,
+	}
+	if l := res.Latency; l != nil {
+		summary.ErrCount = l.ErrorTotal
+		summary.Min = l.Min
+		summary.Max = l.Max
+		summary.Mean = l.Mean
+		summary.StdDev = l.StdDev
+		for _, p := range l.Percentiles {
+			summary.Percentiles = append(summary.Percentiles, &perform_pb.Percentile{Percentile: p.Percentile, Value: p.Value})
+		}
+		for _, m := range l.Metrics {
+			ms := &perform_pb.MetricSummary{Name: m.Name, Kind: m.Kind, Value: m.Value, Min: m.Min, Max: m.Max, Mean: m.Mean}
+			for _, p := range m.Percentiles {
+				ms.Percentiles = append(ms.Percentiles, &perform_pb.Percentile{Percentile: p.Percentile, Value: p.Value})
+			}
+			summary.Metrics = append(summary.Metrics, ms)
+		}
+		for _, ph := range l.Phases {
+			ps := &perform_pb.PhaseSummary{Name: ph.Phase, Count: ph.Count, Min: ph.Min, Max: ph.Max, Mean: ph.Mean}
+			for _, p := range ph.Percentiles {
+				ps.Percentiles = append(ps.Percentiles, &perform_pb.Percentile{Percentile: p.Percentile, Value: p.Value})
+			}
+			summary.Phases = append(summary.Phases, ps)
+		}
+	}
+	if d := res.Drain; d != nil {
+		summary.DrainInFlight = d.InFlight
+		summary.DrainCompleted = d.Completed
+		summary.DrainAborted = d.Aborted
 	}
-	return &perform_pb.ExecutorStatus{Status: perform_pb.Status_STATUS_IDLE}, nil
+	info.Summary = summary
+	return info
 }
 
 func StartGrpcServer(port int, r *runner.BenchMarkRunner) error {
@@ -0,0 +1,97 @@
+package sink
+
+import (
+	"net"
+	"net/url"
+	"perform-cli-framework-go/src/stat"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// graphiteTimeout 连接和写入 Graphite 的超时
+const graphiteTimeout = 3 * time.Second
+
+func init() {
+	Register("graphite", newGraphite)
+}
+
+// graphiteSink 以 Graphite plaintext 协议通过TCP推送，每行为 <path> <value> <timestamp>
+// tags=true 时以 Graphite 1.1 的 <path>;k=v 格式附带标签，连接断开后在下一次推送时重连
+type graphiteSink struct {
+	addr        string
+	conn        net.Conn
+	naming      *naming
+	percentiles []float64
+	suffix      string
+}
+
+func newGraphite(u *url.URL, run Run) (Sink, error) {
+	n, err := newNaming(u, run)
+	if err != nil {
+		return nil, err
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "2003")
+	}
+	s := &graphiteSink{addr: addr, naming: n, percentiles: run.Percentiles}
+	for _, t := range n.tags {
+		if t[1] != "" {
+			s.suffix += ";" + t[0] + "=" + t[1]
+		}
+	}
+	// 创建时连接一次，地址错误时在压测开始前报错
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *graphiteSink) dial() error {
+	conn, err := net.DialTimeout("tcp", s.addr, graphiteTimeout)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	return nil
+}
+
+func (s *graphiteSink) Interval(ss *stat.IntervalStatistic) error {
+	ts := time.Now().Unix()
+	if ss.Histogram != nil && ss.Histogram.EndTimeMs() > 0 {
+		ts = ss.Histogram.EndTimeMs() / 1000
+	}
+	return s.send(IntervalPoints(ss, s.percentiles), ts)
+}
+
+func (s *graphiteSink) Summary(sum *stat.Summary) error {
+	return s.send(SummaryPoints(sum), time.Now().Unix())
+}
+
+func (s *graphiteSink) send(points []Point, ts int64) error {
+	var sb strings.Builder
+	t := " " + strconv.FormatInt(ts, 10) + "\n"
+	for _, p := range points {
+		sb.WriteString(s.naming.path + p.Name + s.suffix + " " + strconv.FormatFloat(p.Value, 'f', -1, 64) + t)
+	}
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return err
+		}
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(graphiteTimeout))
+	if _, err := s.conn.Write([]byte(sb.String())); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (s *graphiteSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,279 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"perform-cli-framework-go/src/stat"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultPrefix 指标名称的默认前缀
+const DefaultPrefix = "perform"
+
+// Sink 区间统计和最终汇总的输出目标，由压测协程以外的单个协程依次调用
+// 自定义的输出目标实现该接口，并在 init 中通过 Register 注册 url 的 scheme
+type Sink interface {
+	// Interval 每个统计区间调用一次，推送跟不上时多个区间会合并为一次
+	Interval(ss *stat.IntervalStatistic) error
+	// Summary 压测结束时以最终汇总调用一次
+	Summary(s *stat.Summary) error
+	Close() error
+}
+
+// Run 输出目标创建时的压测信息
+type Run struct {
+	ID     string
+	Worker string
+	// Instance 执行压测的实例，执行器为执行器名称
+	Instance    string
+	Tags        map[string]string
+	Percentiles []float64
+}
+
+// Factory 按 url 创建本次压测的输出目标
+type Factory func(u *url.URL, run Run) (Sink, error)
+
+var (
+	factories = make(map[string]Factory)
+	m         sync.RWMutex
+)
+
+// Register 注册输出目标，scheme 为 url 的 scheme，重复注册时覆盖
+func Register(scheme string, f Factory) {
+	m.Lock()
+	defer m.Unlock()
+	factories[scheme] = f
+}
+
+// Schemes 已经注册的输出目标
+func Schemes() []string {
+	m.RLock()
+	defer m.RUnlock()
+	res := make([]string, 0, len(factories))
+	for k := range factories {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Open 按 url 创建输出目标，例如 statsd://127.0.0.1:8125、graphite://127.0.0.1:2003?prefix=perform.login
+// 任一个创建失败时关闭已经创建的输出目标并返回错误
+func Open(specs []string, run Run) ([]Sink, error) {
+	res := make([]Sink, 0, len(specs))
+	for _, spec := range specs {
+		s, err := open(spec, run)
+		if err != nil {
+			for _, s := range res {
+				_ = s.Close()
+			}
+			return nil, fmt.Errorf("sink %s: %w", spec, err)
+		}
+		res = append(res, s)
+	}
+	return res, nil
+}
+
+func open(spec string, run Run) (Sink, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	m.RLock()
+	f, ok := factories[u.Scheme]
+	m.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown sink %q, supported: %s", u.Scheme, strings.Join(Schemes(), ", "))
+	}
+	return f(u, run)
+}
+
+// Point 一个指标的值
+type Point struct {
+	Name  string
+	Value float64
+	// Counter 为true时是区间内的计数，否则是瞬时值
+	Counter bool
+}
+
+// IntervalPoints 把区间统计展开为指标，时延单位为us，区间内没有请求时没有时延指标
+// 请求各阶段的耗时以 phase. 开头，自定义指标以 metrics. 开头
+func IntervalPoints(ss *stat.IntervalStatistic, percentiles []float64) []Point {
+	errs := int64(0)
+	for _, v := range ss.Errors {
+		errs += v
+	}
+	requests := errs
+	if ss.Histogram != nil {
+		requests += ss.Histogram.TotalCount()
+	}
+	rps := 0.0
+	if d := ss.Durations - ss.PausedDurations; d > 0 {
+		rps = float64(requests) / (float64(d) / 1000 / 1000)
+	}
+	res := []Point{
+		{Name: "requests", Value: float64(requests), Counter: true},
+		{Name: "errors", Value: float64(errs), Counter: true},
+		{Name: "send_bytes", Value: float64(ss.SendBytes), Counter: true},
+		{Name: "recv_bytes", Value: float64(ss.RecvBytes), Counter: true},
+		{Name: "rps", Value: rps},
+	}
+	res = append(res, phasePoints("phase.", stat.PhaseSummaries(ss.Phases, percentiles), true)...)
+	res = append(res, metricPoints("metrics.", ss.Metrics.Summary(percentiles), true)...)
+	h := ss.Histogram
+	if h == nil || h.TotalCount() == 0 {
+		return res
+	}
+	res = append(res,
+		Point{Name: "latency.min", Value: float64(h.Min())},
+		Point{Name: "latency.mean", Value: h.Mean()},
+		Point{Name: "latency.max", Value: float64(h.Max())},
+	)
+	for _, p := range percentiles {
+		if p < 100 {
+			res = append(res, Point{Name: "latency." + percentileName(p), Value: float64(h.ValueAtPercentile(p))})
+		}
+	}
+	return res
+}
+
+// SummaryPoints 把最终汇总展开为指标，名称以 summary. 开头
+func SummaryPoints(s *stat.Summary) []Point {
+	res := []Point{
+		{Name: "summary.requests", Value: float64(s.SendTotal + s.ErrorTotal)},
+		{Name: "summary.errors", Value: float64(s.ErrorTotal)},
+		{Name: "summary.latency.min", Value: float64(s.Min)},
+		{Name: "summary.latency.mean", Value: s.Mean},
+		{Name: "summary.latency.stddev", Value: s.StdDev},
+		{Name: "summary.latency.max", Value: float64(s.Max)},
+	}
+	for _, p := range s.Percentiles {
+		if p.Percentile < 100 {
+			res = append(res, Point{Name: "summary.latency." + percentileName(p.Percentile), Value: float64(p.Value)})
+		}
+	}
+	res = append(res, phasePoints("summary.phase.", s.Phases, false)...)
+	return append(res, metricPoints("summary.metrics.", s.Metrics, false)...)
+}
+
+// phasePoints 把各阶段的耗时展开为 <phase>.count、<phase>.mean 等指标，单位us
+// counter 为true时个数是区间内的增量
+func phasePoints(prefix string, ps []stat.PhaseSummary, counter bool) []Point {
+	res := make([]Point, 0, len(ps)*(4+len(stat.SummaryPercentiles)))
+	for _, ph := range ps {
+		name := prefix + sanitize(ph.Phase)
+		res = append(res,
+			Point{Name: name + ".count", Value: float64(ph.Count), Counter: counter},
+			Point{Name: name + ".min", Value: float64(ph.Min)},
+			Point{Name: name + ".mean", Value: ph.Mean},
+			Point{Name: name + ".max", Value: float64(ph.Max)},
+		)
+		for _, p := range ph.Percentiles {
+			if p.Percentile < 100 {
+				res = append(res, Point{Name: name + "." + percentileName(p.Percentile), Value: float64(p.Value)})
+			}
+		}
+	}
+	return res
+}
+
+// metricPoints 把自定义指标展开为指标，计数和仪表为 <name>，直方图为 <name>.count、<name>.mean 等
+// counter 为true时计数是区间内的增量
+func metricPoints(prefix string, ms []stat.MetricSummary, counter bool) []Point {
+	res := make([]Point, 0, len(ms))
+	for _, m := range ms {
+		name := prefix + sanitizeMetric(m.Name)
+		switch m.Kind {
+		case stat.MetricCounter:
+			res = append(res, Point{Name: name, Value: m.Value, Counter: counter})
+		case stat.MetricGauge:
+			res = append(res, Point{Name: name, Value: m.Value})
+		case stat.MetricHistogram:
+			res = append(res, Point{Name: name + ".count", Value: m.Value, Counter: counter})
+			if m.Value == 0 {
+				continue
+			}
+			res = append(res,
+				Point{Name: name + ".min", Value: float64(m.Min)},
+				Point{Name: name + ".mean", Value: m.Mean},
+				Point{Name: name + ".max", Value: float64(m.Max)},
+			)
+			for _, p := range m.Percentiles {
+				if p.Percentile < 100 {
+					res = append(res, Point{Name: name + "." + percentileName(p.Percentile), Value: float64(p.Value)})
+				}
+			}
+		}
+	}
+	return res
+}
+
+// percentileName 指标路径中的点是分隔符，p99.9 写为 p99_9
+func percentileName(p float64) string {
+	return strings.ReplaceAll(stat.PercentileName(p), ".", "_")
+}
+
+// sanitize 替换指标路径和标签中的分隔符和空白
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ' ', '\t', '\n', ':', '|', ';', ',', '=', '#', '@':
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+// sanitizeMetric 替换自定义指标名称中的分隔符和空白，保留点作为层级
+func sanitizeMetric(s string) string {
+	parts := strings.Split(s, ".")
+	for i := range parts {
+		parts[i] = sanitize(parts[i])
+	}
+	return strings.Join(parts, ".")
+}
+
+// naming 内置输出目标共用的指标命名
+// 默认路径为 <prefix>.<worker>.<instance>.<metric>，tags=true 时路径为 <prefix>.<metric>，压测信息作为标签
+type naming struct {
+	prefix string
+	path   string
+	tagged bool
+	tags   [][2]string
+}
+
+func newNaming(u *url.URL, run Run) (*naming, error) {
+	q := u.Query()
+	n := &naming{prefix: DefaultPrefix}
+	if p := q.Get("prefix"); p != "" {
+		n.prefix = strings.Trim(p, ".")
+	}
+	switch q.Get("tags") {
+	case "", "false", "0":
+	case "true", "1":
+		n.tagged = true
+	default:
+		return nil, errors.New("tags must be true or false")
+	}
+	if !n.tagged {
+		n.path = n.prefix + "." + sanitize(run.Worker) + "." + sanitize(run.Instance) + "."
+		return n, nil
+	}
+	n.path = n.prefix + "."
+	n.tags = [][2]string{{"run_id", run.ID}, {"worker", run.Worker}, {"instance", run.Instance}}
+	keys := make([]string, 0, len(run.Tags))
+	for k := range run.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		n.tags = append(n.tags, [2]string{k, run.Tags[k]})
+	}
+	for i := range n.tags {
+		n.tags[i] = [2]string{sanitize(n.tags[i][0]), sanitize(n.tags[i][1])}
+	}
+	return n, nil
+}
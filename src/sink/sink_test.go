@@ -0,0 +1,275 @@
+package sink
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"perform-cli-framework-go/src/stat"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+var testRun = Run{
+	ID:          "r1",
+	Worker:      "HttpWorker",
+	Instance:    "e1",
+	Tags:        map[string]string{"env": "pre prod"},
+	Percentiles: []float64{50, 99.9},
+}
+
+var testSummary = &stat.Summary{
+	SendTotal:   90,
+	ErrorTotal:  10,
+	Min:         100,
+	Max:         900,
+	Mean:        250.5,
+	Percentiles: []stat.Percentile{{Percentile: 99, Value: 800}, {Percentile: 100, Value: 900}},
+}
+
+// listenUDP 监听本机的随机UDP端口，返回地址和读取收到的所有包的函数
+func listenUDP(t *testing.T) (string, func() []string) {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+	return pc.LocalAddr().String(), func() []string {
+		var packets []string
+		buf := make([]byte, 64*1024)
+		for {
+			_ = pc.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			n, _, err := pc.ReadFrom(buf)
+			if err != nil {
+				return packets
+			}
+			packets = append(packets, string(buf[:n]))
+		}
+	}
+}
+
+// listenTCP 监听本机的随机TCP端口，返回地址和按行读取的channel
+func listenTCP(t *testing.T) (string, <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	lines := make(chan string, 1024)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		sc := bufio.NewScanner(conn)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	return l.Addr().String(), lines
+}
+
+func readLines(t *testing.T, lines <-chan string, n int) []string {
+	t.Helper()
+	res := make([]string, 0, n)
+	for len(res) < n {
+		select {
+		case l := <-lines:
+			res = append(res, l)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d lines, want %d: %q", len(res), n, res)
+		}
+	}
+	return res
+}
+
+func openSink(t *testing.T, spec string) Sink {
+	t.Helper()
+	ss, err := Open([]string{spec}, testRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ss[0].Close() })
+	return ss[0]
+}
+
+func TestStatsdLines(t *testing.T) {
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{
+			"perform.HttpWorker.e1.summary.requests:100|g",
+			"perform.HttpWorker.e1.summary.errors:10|g",
+			"perform.HttpWorker.e1.summary.latency.mean:250.5|g",
+			"perform.HttpWorker.e1.summary.latency.p99:800|g",
+		}},
+		{"?prefix=bench.&tags=true", []string{
+			"bench.summary.requests:100|g|#run_id:r1,worker:HttpWorker,instance:e1,env:pre_prod",
+			"bench.summary.latency.max:900|g|#run_id:r1,worker:HttpWorker,instance:e1,env:pre_prod",
+		}},
+	}
+	for _, c := range cases {
+		addr, read := listenUDP(t)
+		s := openSink(t, "statsd://"+addr+c.query)
+		if err := s.Summary(testSummary); err != nil {
+			t.Fatal(err)
+		}
+		packets := read()
+		if len(packets) != 1 {
+			t.Fatalf("%s: got %d packets, want 1", c.query, len(packets))
+		}
+		got := strings.Split(packets[0], "\n")
+		for _, w := range c.want {
+			if !slices.Contains(got, w) {
+				t.Errorf("%s: missing line %q in %q", c.query, w, got)
+			}
+		}
+		// 分位数中的 100 已经作为 latency.max 输出，不再重复
+		if n := strings.Count(packets[0], "summary.latency.max:"); n != 1 {
+			t.Errorf("%s: got %d latency.max lines, want 1", c.query, n)
+		}
+	}
+}
+
+func TestStatsdInterval(t *testing.T) {
+	addr, read := listenUDP(t)
+	s := openSink(t, "statsd://"+addr)
+	h := hdrhistogram.New(1, 1000*1000, 3)
+	for i := int64(1); i <= 1000; i++ {
+		_ = h.RecordValue(i)
+	}
+	ss := &stat.IntervalStatistic{
+		Durations: 2 * 1000 * 1000,
+		SendBytes: 4096,
+		Errors:    map[string]int64{"timeout": 3, "reset": 1},
+		Histogram: h,
+	}
+	if err := s.Interval(ss); err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.Join(read(), "\n"), "\n")
+	for _, w := range []string{
+		"perform.HttpWorker.e1.requests:1004|c",
+		"perform.HttpWorker.e1.errors:4|c",
+		"perform.HttpWorker.e1.send_bytes:4096|c",
+		"perform.HttpWorker.e1.rps:502|g",
+		"perform.HttpWorker.e1.latency.min:1|g",
+		"perform.HttpWorker.e1.latency.p99_9:999|g",
+	} {
+		if !slices.Contains(got, w) {
+			t.Errorf("missing line %q in %q", w, got)
+		}
+	}
+}
+
+func TestStatsdSplitPackets(t *testing.T) {
+	addr, read := listenUDP(t)
+	s := openSink(t, "statsd://"+addr+"?tags=true")
+	sum := &stat.Summary{}
+	for i := 0; i < 100; i++ {
+		sum.Metrics = append(sum.Metrics, stat.MetricSummary{
+			Name: fmt.Sprintf("upstream.pool_%03d.connections", i), Kind: stat.MetricGauge, Value: float64(i),
+		})
+	}
+	if err := s.Summary(sum); err != nil {
+		t.Fatal(err)
+	}
+	packets := read()
+	if len(packets) < 2 {
+		t.Fatalf("got %d packets, want the points split into several", len(packets))
+	}
+	seen := make(map[string]bool)
+	for _, p := range packets {
+		if len(p) > statsdMaxPacket {
+			t.Errorf("packet of %d bytes exceeds %d", len(p), statsdMaxPacket)
+		}
+		for _, l := range strings.Split(p, "\n") {
+			if !strings.HasSuffix(l, "|#run_id:r1,worker:HttpWorker,instance:e1,env:pre_prod") {
+				t.Errorf("line split across packets: %q", l)
+			}
+			seen[l] = true
+		}
+	}
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("perform.summary.metrics.upstream.pool_%03d.connections:%d|g", i, i)
+		if !seen[name+"|#run_id:r1,worker:HttpWorker,instance:e1,env:pre_prod"] {
+			t.Errorf("missing metric %s", name)
+		}
+	}
+}
+
+func TestGraphiteLines(t *testing.T) {
+	cases := []struct {
+		query  string
+		suffix string
+		want   []string
+	}{
+		{"", "", []string{
+			"perform.HttpWorker.e1.summary.requests 100",
+			"perform.HttpWorker.e1.summary.latency.stddev 0",
+			"perform.HttpWorker.e1.summary.latency.p99 800",
+		}},
+		{"?prefix=bench&tags=true", ";run_id=r1;worker=HttpWorker;instance=e1;env=pre_prod", []string{
+			"bench.summary.requests;run_id=r1;worker=HttpWorker;instance=e1;env=pre_prod 100",
+			"bench.summary.errors;run_id=r1;worker=HttpWorker;instance=e1;env=pre_prod 10",
+		}},
+	}
+	for _, c := range cases {
+		addr, lines := listenTCP(t)
+		s := openSink(t, "graphite://"+addr+c.query)
+		before := time.Now().Unix()
+		if err := s.Summary(testSummary); err != nil {
+			t.Fatal(err)
+		}
+		got := readLines(t, lines, len(SummaryPoints(testSummary)))
+		paths := make([]string, 0, len(got))
+		for _, l := range got {
+			fields := strings.Split(l, " ")
+			if len(fields) != 3 {
+				t.Fatalf("%s: want <path> <value> <timestamp>, got %q", c.query, l)
+			}
+			ts, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil || ts < before || ts > time.Now().Unix() {
+				t.Errorf("%s: bad timestamp in %q", c.query, l)
+			}
+			if !strings.HasSuffix(fields[0], c.suffix) {
+				t.Errorf("%s: missing tags in %q", c.query, l)
+			}
+			paths = append(paths, fields[0]+" "+fields[1])
+		}
+		for _, w := range c.want {
+			if !slices.Contains(paths, w) {
+				t.Errorf("%s: missing line %q in %q", c.query, w, paths)
+			}
+		}
+	}
+}
+
+func TestGraphiteIntervalTimestamp(t *testing.T) {
+	addr, lines := listenTCP(t)
+	s := openSink(t, "graphite://"+addr)
+	h := hdrhistogram.New(1, 1000*1000, 3)
+	_ = h.RecordValue(200)
+	h.SetEndTimeMs(1700000000500)
+	ss := &stat.IntervalStatistic{Durations: 1000 * 1000, Histogram: h}
+	if err := s.Interval(ss); err != nil {
+		t.Fatal(err)
+	}
+	got := readLines(t, lines, len(IntervalPoints(ss, testRun.Percentiles)))
+	for _, l := range got {
+		if !strings.HasSuffix(l, " 1700000000") {
+			t.Errorf("want the interval end as timestamp, got %q", l)
+		}
+	}
+	if !slices.Contains(got, "perform.HttpWorker.e1.latency.p50 200 1700000000") {
+		t.Errorf("missing p50 in %q", got)
+	}
+}
@@ -0,0 +1,93 @@
+package sink
+
+import (
+	"net"
+	"net/url"
+	"perform-cli-framework-go/src/stat"
+	"strconv"
+	"strings"
+)
+
+// statsdMaxPacket 单个UDP包的上限，小于常见的以太网MTU，避免分片
+const statsdMaxPacket = 1432
+
+func init() {
+	Register("statsd", newStatsd)
+}
+
+// statsdSink 以 StatsD 协议通过UDP推送，计数为 |c，其余为 |g
+// tags=true 时以 DogStatsD 的 |#k:v 格式附带标签
+type statsdSink struct {
+	conn        net.Conn
+	naming      *naming
+	percentiles []float64
+	suffix      string
+	buf         []byte
+}
+
+func newStatsd(u *url.URL, run Run) (Sink, error) {
+	n, err := newNaming(u, run)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "8125")
+	}
+	conn, err := net.Dial("udp", host)
+	if err != nil {
+		return nil, err
+	}
+	s := &statsdSink{conn: conn, naming: n, percentiles: run.Percentiles}
+	if n.tagged {
+		kvs := make([]string, 0, len(n.tags))
+		for _, t := range n.tags {
+			if t[1] != "" {
+				kvs = append(kvs, t[0]+":"+t[1])
+			}
+		}
+		s.suffix = "|#" + strings.Join(kvs, ",")
+	}
+	return s, nil
+}
+
+func (s *statsdSink) Interval(ss *stat.IntervalStatistic) error {
+	return s.send(IntervalPoints(ss, s.percentiles))
+}
+
+func (s *statsdSink) Summary(sum *stat.Summary) error {
+	return s.send(SummaryPoints(sum))
+}
+
+// send 按行拼接指标，超过单个包的上限时分包发送
+func (s *statsdSink) send(points []Point) error {
+	s.buf = s.buf[:0]
+	for _, p := range points {
+		line := s.naming.path + p.Name + ":" + strconv.FormatFloat(p.Value, 'f', -1, 64)
+		if p.Counter {
+			line += "|c"
+		} else {
+			line += "|g"
+		}
+		line += s.suffix
+		if len(s.buf) > 0 && len(s.buf)+1+len(line) > statsdMaxPacket {
+			if _, err := s.conn.Write(s.buf); err != nil {
+				return err
+			}
+			s.buf = s.buf[:0]
+		}
+		if len(s.buf) > 0 {
+			s.buf = append(s.buf, '\n')
+		}
+		s.buf = append(s.buf, line...)
+	}
+	if len(s.buf) == 0 {
+		return nil
+	}
+	_, err := s.conn.Write(s.buf)
+	return err
+}
+
+func (s *statsdSink) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,68 @@
+package stat
+
+import (
+	"fmt"
+	"perform-cli-framework-go/src/logger"
+)
+
+// GeneratorStats 压测机自身在区间内的资源使用，用于判断瓶颈是被测服务还是压测机
+type GeneratorStats struct {
+	// CPU 进程的CPU使用率(%)，100表示占满一个核
+	CPU float64
+	// Cores 压测机的CPU核数
+	Cores int64
+	// Goroutines 取统计时的协程数
+	Goroutines int64
+	// GCCount 区间内的GC次数
+	GCCount int64
+	// GCPause 区间内GC暂停的总时长，单位微秒
+	GCPause int64
+	// GCPauseMax 区间内最长的一次GC暂停，单位微秒
+	GCPauseMax int64
+	// SchedLatencyP99 协程从就绪到开始运行的调度延迟的p99，单位微秒
+	SchedLatencyP99 int64
+	// SchedLatencyMax 调度延迟的最大值，单位微秒
+	SchedLatencyMax int64
+	// TargetRate 取统计时的目标速率，0表示不限速
+	TargetRate int64
+	// LimiterLag 区间内实际发压落后于目标速率的时长，单位微秒
+	LimiterLag int64
+	// Saturated 压测机跟不上目标速率
+	Saturated bool
+}
+
+// MergeGenerator 合并两份压测机统计并返回合并结果，dst为nil时返回src的拷贝
+// GC次数、暂停时长和落后时长相加，其余取最大值，只要有一份跟不上目标速率就视为跟不上
+func MergeGenerator(dst, src *GeneratorStats) *GeneratorStats {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		cp := *src
+		return &cp
+	}
+	dst.CPU = max(dst.CPU, src.CPU)
+	dst.Cores = max(dst.Cores, src.Cores)
+	dst.Goroutines = max(dst.Goroutines, src.Goroutines)
+	dst.GCCount += src.GCCount
+	dst.GCPause += src.GCPause
+	dst.GCPauseMax = max(dst.GCPauseMax, src.GCPauseMax)
+	dst.SchedLatencyP99 = max(dst.SchedLatencyP99, src.SchedLatencyP99)
+	dst.SchedLatencyMax = max(dst.SchedLatencyMax, src.SchedLatencyMax)
+	dst.TargetRate = max(dst.TargetRate, src.TargetRate)
+	dst.LimiterLag += src.LimiterLag
+	dst.Saturated = dst.Saturated || src.Saturated
+	return dst
+}
+
+// String 压测机统计的单行描述
+func (g *GeneratorStats) String() string {
+	return fmt.Sprintf("CPU: %.1f%% of %d cores | Goroutines: %d | GC: %d (pause %s, max %s) | Sched p99: %s, max %s | Lag: %s",
+		g.CPU, g.Cores, g.Goroutines, g.GCCount, FormatLatency(g.GCPause), FormatLatency(g.GCPauseMax),
+		FormatLatency(g.SchedLatencyP99), FormatLatency(g.SchedLatencyMax), FormatLatency(g.LimiterLag))
+}
+
+// LogSelf 打印压测机统计
+func (g *GeneratorStats) LogSelf() {
+	logger.Info("[Generator] %s", g)
+}
@@ -1,9 +1,9 @@
 package hdrImpl
 
 import (
-	"perform-cli-framework-go/src/logger"
 	"perform-cli-framework-go/src/stat"
 	"perform-cli-framework-go/src/utils"
+	"sync"
 	"sync/atomic"
 
 	"github.com/HdrHistogram/hdrhistogram-go"
@@ -17,8 +17,21 @@ type HdrHistogramStat struct {
 	HdrHistogram      *hdrhistogram.Histogram
 	Recorder          *Recorder
 	timePoint         int64
+	errM              sync.Mutex
+	// errors 区间内按错误信息分类的错误数，超过 maxErrorKinds 种的归入 otherErrors
+	errors map[string]int64
+	// metrics 工作器记录的自定义指标
+	metrics stat.MetricRecorder
+	// phases 请求各阶段的耗时
+	phases *stat.PhaseRecorder
 }
 
+const (
+	maxErrorKinds = 32
+	maxErrorLen   = 120
+	otherErrors   = "other"
+)
+
 func New(timeUs int64) *HdrHistogramStat {
 
 	return &HdrHistogramStat{
@@ -29,6 +42,7 @@ func New(timeUs int64) *HdrHistogramStat {
 		HdrHistogram:      hdrhistogram.New(1, timeUs, 5),
 		Recorder:          NewRecorder(timeUs),
 		timePoint:         utils.GetTimeUs(),
+		phases:            stat.NewPhaseRecorder(timeUs),
 	}
 }
 
@@ -37,19 +51,28 @@ func (h *HdrHistogramStat) Reset() {
 	h.IntervalRecvBytes.GetThenReset()
 	h.SendTotal.Store(0)
 	h.SendErr.Store(0)
-	// 输出基础统计
-	logger.Info("Latency Statistics (us):")
-	logger.Info("  Min      : %d", h.HdrHistogram.Min())
-	logger.Info("  Max      : %d", h.HdrHistogram.Max())
-	logger.Info("  Mean     : %.2f", h.HdrHistogram.Mean())
-	logger.Info("  StdDev   : %.2f", h.HdrHistogram.StdDev())
-
-	// 输出分位数统计（P50, P90, P95, P99）
-	logger.Info("Percentiles:")
-	logger.Info("  P90    : %d", h.HdrHistogram.ValueAtPercentile(90.0))
-	logger.Info("  P95    : %d", h.HdrHistogram.ValueAtPercentile(95.0))
-	logger.Info("  P99    : %d", h.HdrHistogram.ValueAtPercentile(99.0))
 	h.HdrHistogram.Reset()
+	h.metrics.Reset()
+	h.phases.Reset()
+}
+
+// GetSummary 返回自上次Reset以来的汇总统计，percentiles为需要计算的分位数
+func (h *HdrHistogramStat) GetSummary(percentiles []float64) *stat.Summary {
+	ps := make([]stat.Percentile, 0, len(percentiles))
+	for _, p := range percentiles {
+		ps = append(ps, stat.Percentile{Percentile: p, Value: h.HdrHistogram.ValueAtPercentile(p)})
+	}
+	return &stat.Summary{
+		SendTotal:   h.SendTotal.Load(),
+		ErrorTotal:  h.SendErr.Load(),
+		Min:         h.HdrHistogram.Min(),
+		Max:         h.HdrHistogram.Max(),
+		Mean:        h.HdrHistogram.Mean(),
+		StdDev:      h.HdrHistogram.StdDev(),
+		Percentiles: ps,
+		Metrics:     h.metrics.Total().Summary(percentiles),
+		Phases:      stat.PhaseSummaries(h.phases.Total(), percentiles),
+	}
 }
 
 func (h *HdrHistogramStat) AddLatency(latency int64) {
@@ -70,7 +93,35 @@ func (h *HdrHistogramStat) RecordBytes(value int64, isSend bool) {
 }
 func (h *HdrHistogramStat) RecordErr(errMsg string) {
 	h.SendErr.Add(1)
+	if len(errMsg) > maxErrorLen {
+		errMsg = errMsg[:maxErrorLen]
+	}
+	h.errM.Lock()
+	defer h.errM.Unlock()
+	if h.errors == nil {
+		h.errors = make(map[string]int64)
+	}
+	if _, ok := h.errors[errMsg]; !ok && len(h.errors) >= maxErrorKinds {
+		errMsg = otherErrors
+	}
+	h.errors[errMsg]++
+}
+func (h *HdrHistogramStat) Count(name string, delta int64) {
+	h.metrics.Count(name, delta)
 }
+
+func (h *HdrHistogramStat) Gauge(name string, value float64) {
+	h.metrics.Gauge(name, value)
+}
+
+func (h *HdrHistogramStat) Observe(name string, value int64) {
+	h.metrics.Observe(name, value)
+}
+
+func (h *HdrHistogramStat) RecordPhase(phase string, us int64) {
+	h.phases.Record(phase, us)
+}
+
 func (h *HdrHistogramStat) GetIntervalStatistic() *stat.IntervalStatistic {
 	// 获取一定时间间隔的统计数据
 	hdr := h.Recorder.GetIntervalHistogram()
@@ -78,8 +129,16 @@ func (h *HdrHistogramStat) GetIntervalStatistic() *stat.IntervalStatistic {
 	sendErr := h.SendErr.Load()
 	recvBytes := h.IntervalRecvBytes.GetThenReset()
 	sendBytes := h.IntervalSendBytes.GetThenReset()
+	h.errM.Lock()
+	errs := h.errors
+	h.errors = nil
+	h.errM.Unlock()
 	now := utils.GetTimeUs()
 	d := now - h.timePoint
+	// 保留直方图的拷贝，recorder下次切换时会重置该直方图
+	histogram := stat.CopyHistogram(hdr)
+	histogram.SetStartTimeMs(h.timePoint / 1000)
+	histogram.SetEndTimeMs(now / 1000)
 	h.timePoint = now
 	records := make([]stat.Record, 0)
 	sn := hdr.Export()
@@ -99,5 +158,9 @@ func (h *HdrHistogramStat) GetIntervalStatistic() *stat.IntervalStatistic {
 		RecvBytes:  recvBytes,
 		Durations:  d,
 		Records:    records,
+		Histogram:  histogram,
+		Errors:     errs,
+		Metrics:    h.metrics.Interval(),
+		Phases:     h.phases.Interval(),
 	}
 }
@@ -0,0 +1,106 @@
+package stat
+
+import (
+	"encoding/base64"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// EncodeHistogram 将直方图编码为 HdrHistogram V2 compressed 格式(二进制，未做base64)
+// 编码中带有最小值、最大值和有效位数，不同执行器的编码可以无损合并
+func EncodeHistogram(h *hdrhistogram.Histogram) ([]byte, error) {
+	encoded, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(string(encoded))
+}
+
+// DecodeHistogram 解码 EncodeHistogram 的结果
+func DecodeHistogram(b []byte) (*hdrhistogram.Histogram, error) {
+	return hdrhistogram.Decode([]byte(base64.StdEncoding.EncodeToString(b)))
+}
+
+// CopyHistogram 深拷贝直方图
+func CopyHistogram(h *hdrhistogram.Histogram) *hdrhistogram.Histogram {
+	if h == nil {
+		return nil
+	}
+	cp := hdrhistogram.Import(h.Export())
+	cp.SetStartTimeMs(h.StartTimeMs())
+	cp.SetEndTimeMs(h.EndTimeMs())
+	cp.SetTag(h.Tag())
+	return cp
+}
+
+// MergeHistogram 将src合并到dst并返回合并结果，dst的范围不足时会扩大范围后再合并
+// 范围和有效位数相同的直方图合并是无损的
+func MergeHistogram(dst, src *hdrhistogram.Histogram) *hdrhistogram.Histogram {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		return CopyHistogram(src)
+	}
+	if src.HighestTrackableValue() > dst.HighestTrackableValue() ||
+		src.LowestTrackableValue() < dst.LowestTrackableValue() ||
+		src.SignificantFigures() > dst.SignificantFigures() {
+		wider := hdrhistogram.New(
+			min(dst.LowestTrackableValue(), src.LowestTrackableValue()),
+			max(dst.HighestTrackableValue(), src.HighestTrackableValue()),
+			int(max(dst.SignificantFigures(), src.SignificantFigures())),
+		)
+		wider.Merge(dst)
+		wider.SetStartTimeMs(dst.StartTimeMs())
+		wider.SetEndTimeMs(dst.EndTimeMs())
+		wider.SetTag(dst.Tag())
+		dst = wider
+	}
+	dst.Merge(src)
+	if src.StartTimeMs() > 0 && (dst.StartTimeMs() == 0 || src.StartTimeMs() < dst.StartTimeMs()) {
+		dst.SetStartTimeMs(src.StartTimeMs())
+	}
+	if src.EndTimeMs() > dst.EndTimeMs() {
+		dst.SetEndTimeMs(src.EndTimeMs())
+	}
+	return dst
+}
+
+// MergeEncoded 合并多个 EncodeHistogram 编码的直方图，例如多个执行器 PerformStats.histogram 字段
+func MergeEncoded(encoded ...[]byte) (*hdrhistogram.Histogram, error) {
+	var merged *hdrhistogram.Histogram
+	for _, b := range encoded {
+		if len(b) == 0 {
+			continue
+		}
+		h, err := DecodeHistogram(b)
+		if err != nil {
+			return nil, err
+		}
+		merged = MergeHistogram(merged, h)
+	}
+	return merged, nil
+}
+
+// LatencySummary 根据区间直方图计算时延汇总，没有直方图时返回nil
+func (i *IntervalStatistic) LatencySummary(percentiles []float64) *Summary {
+	h := i.Histogram
+	if h == nil {
+		return nil
+	}
+	ps := make([]Percentile, 0, len(percentiles))
+	for _, p := range percentiles {
+		ps = append(ps, Percentile{Percentile: p, Value: h.ValueAtPercentile(p)})
+	}
+	return &Summary{
+		SendTotal:   i.SendTotal,
+		ErrorTotal:  i.ErrorTotal,
+		Min:         h.Min(),
+		Max:         h.Max(),
+		Mean:        h.Mean(),
+		StdDev:      h.StdDev(),
+		Percentiles: ps,
+		Metrics:     i.Metrics.Summary(percentiles),
+		Phases:      PhaseSummaries(i.Phases, percentiles),
+	}
+}
@@ -0,0 +1,197 @@
+package stat
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// HlogFormatVersion 写入的 HdrHistogram 日志格式版本
+const HlogFormatVersion = "1.3"
+
+// hlogMaxUnitRatio 直方图的值单位为微秒，Interval_Max 列以毫秒输出
+const hlogMaxUnitRatio = 1000.0
+
+// HlogWriter 以 HdrHistogram 日志格式(.hlog)写入区间直方图，与 Java 版 HistogramLogWriter 一致
+// 时间戳为相对 BaseTime 的秒数，可以用 HistogramLogAnalyzer、HistogramLogProcessor 等工具分析
+type HlogWriter struct {
+	w io.Writer
+	// baseMs 时间戳的基准，unix时间戳(ms)
+	baseMs int64
+	tag    string
+}
+
+// NewHlogWriter 写入日志头，startMs为日志的开始时间(ms)，同时作为时间戳的基准，tag为没有tag的直方图使用的tag
+func NewHlogWriter(w io.Writer, startMs int64, tag string, comments ...string) (*HlogWriter, error) {
+	hw := &HlogWriter{w: w, baseMs: startMs, tag: HlogTag(tag)}
+	var sb strings.Builder
+	for _, c := range comments {
+		sb.WriteString("#" + c + "\n")
+	}
+	start := float64(startMs) / 1000
+	fmt.Fprintf(&sb, "#[Histogram log format version %s]\n", HlogFormatVersion)
+	fmt.Fprintf(&sb, "#[StartTime: %.3f (seconds since epoch), %s]\n", start, time.UnixMilli(startMs).Format(time.RFC3339))
+	fmt.Fprintf(&sb, "#[BaseTime: %.3f (seconds since epoch)]\n", start)
+	sb.WriteString("\"StartTimestamp\",\"Interval_Length\",\"Interval_Max\",\"Interval_Compressed_Histogram\"\n")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return nil, err
+	}
+	return hw, nil
+}
+
+// HlogTag 日志中的tag不能包含逗号、空白和换行，替换为下划线
+func HlogTag(tag string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ',' || r == ' ' || r == '\t' || r == '\r' || r == '\n' {
+			return '_'
+		}
+		return r
+	}, tag)
+}
+
+// Write 写入一个区间直方图，时间范围为直方图的 StartTimeMs 和 EndTimeMs
+func (hw *HlogWriter) Write(h *hdrhistogram.Histogram) error {
+	encoded, err := h.Encode(hdrhistogram.V2CompressedEncodingCookieBase)
+	if err != nil {
+		return err
+	}
+	line := fmt.Sprintf("%.3f,%.3f,%.3f,%s\n",
+		float64(h.StartTimeMs()-hw.baseMs)/1000,
+		float64(h.EndTimeMs()-h.StartTimeMs())/1000,
+		float64(h.Max())/hlogMaxUnitRatio,
+		encoded)
+	tag := hw.tag
+	if h.Tag() != "" {
+		tag = HlogTag(h.Tag())
+	}
+	if tag != "" {
+		line = "Tag=" + tag + "," + line
+	}
+	_, err = io.WriteString(hw.w, line)
+	return err
+}
+
+// ReadHlog 读取日志中的全部区间直方图，直方图的 StartTimeMs、EndTimeMs 为绝对时间，按开始时间排序
+func ReadHlog(path string) ([]*hdrhistogram.Histogram, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	var (
+		res               []*hdrhistogram.Histogram
+		startSec, baseSec float64
+		hasStart, hasBase bool
+		sc                = bufio.NewScanner(f)
+		lineNo            int
+	)
+	// 压缩的直方图可能超过Scanner默认的单行上限
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "\""):
+			continue
+		case strings.HasPrefix(line, "#[StartTime: "):
+			startSec, err = parseHlogTime(line, "#[StartTime: ")
+			hasStart = err == nil
+			continue
+		case strings.HasPrefix(line, "#[BaseTime: "):
+			baseSec, err = parseHlogTime(line, "#[BaseTime: ")
+			hasBase = err == nil
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+		tag := ""
+		if rest, ok := strings.CutPrefix(line, "Tag="); ok {
+			tag, line, _ = strings.Cut(rest, ",")
+		}
+		fields := strings.SplitN(line, ",", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%s:%d: invalid interval line", path, lineNo)
+		}
+		ts, err1 := strconv.ParseFloat(fields[0], 64)
+		length, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("%s:%d: invalid timestamp", path, lineNo)
+		}
+		h, err := hdrhistogram.Decode([]byte(fields[3]))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNo, err)
+		}
+		if !hasStart {
+			startSec, hasStart = ts, true
+		}
+		// 没有BaseTime时与Java版一致：时间戳比StartTime早一年以上说明是相对时间
+		if !hasBase {
+			if ts < startSec-365*24*3600 {
+				baseSec = startSec
+			}
+			hasBase = true
+		}
+		abs := ts + baseSec
+		h.SetStartTimeMs(int64(abs * 1000))
+		h.SetEndTimeMs(int64((abs + length) * 1000))
+		h.SetTag(tag)
+		res = append(res, h)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(res, func(a, b int) bool {
+		return res[a].StartTimeMs() < res[b].StartTimeMs()
+	})
+	return res, nil
+}
+
+func parseHlogTime(line, prefix string) (float64, error) {
+	v, _, _ := strings.Cut(strings.TrimPrefix(line, prefix), " ")
+	return strconv.ParseFloat(v, 64)
+}
+
+// MergeHlogs 合并多个日志(例如多个执行器的日志)中的区间直方图，按开始时间排序
+// intervalMs大于0时把开始时间落在同一个周期内的直方图合并为一个没有tag的直方图，否则保留各自的tag
+func MergeHlogs(paths []string, intervalMs int64) ([]*hdrhistogram.Histogram, error) {
+	var all []*hdrhistogram.Histogram
+	for _, p := range paths {
+		hs, err := ReadHlog(p)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, hs...)
+	}
+	sort.SliceStable(all, func(a, b int) bool {
+		return all[a].StartTimeMs() < all[b].StartTimeMs()
+	})
+	if intervalMs <= 0 || len(all) == 0 {
+		return all, nil
+	}
+	start := all[0].StartTimeMs()
+	res := make([]*hdrhistogram.Histogram, 0)
+	idx := int64(-1)
+	for _, h := range all {
+		i := (h.StartTimeMs() - start) / intervalMs
+		if i != idx {
+			res = append(res, nil)
+			idx = i
+		}
+		last := len(res) - 1
+		merged := MergeHistogram(res[last], h)
+		merged.SetTag("")
+		merged.SetStartTimeMs(start + i*intervalMs)
+		merged.SetEndTimeMs(start + (i+1)*intervalMs)
+		res[last] = merged
+	}
+	return res, nil
+}
@@ -0,0 +1,314 @@
+package stat
+
+import (
+	"fmt"
+	"perform-cli-framework-go/src/logger"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// 自定义指标的类型
+const (
+	MetricCounter   = "counter"
+	MetricGauge     = "gauge"
+	MetricHistogram = "histogram"
+)
+
+const (
+	// maxMetrics 每种自定义指标最多的名称个数，超过后新的名称不再记录
+	maxMetrics = 64
+	// MetricHighest 自定义直方图可以记录的最大值，超过或小于0的值不记录
+	MetricHighest = int64(1e12)
+	// MetricSigFigs 自定义直方图的有效位数
+	MetricSigFigs = 2
+)
+
+// Metrics 工作器通过 GoData 记录的自定义指标
+// 区间统计中计数和直方图为区间内的值，仪表为区间内最后一次记录的值
+type Metrics struct {
+	Counters map[string]int64   `json:",omitempty"`
+	Gauges   map[string]float64 `json:",omitempty"`
+	// Histograms 记录的值的分布，单位由工作器决定
+	Histograms map[string]*hdrhistogram.Histogram `json:"-"`
+}
+
+// NewMetricHistogram 创建自定义指标使用的直方图
+func NewMetricHistogram() *hdrhistogram.Histogram {
+	return hdrhistogram.New(1, MetricHighest, MetricSigFigs)
+}
+
+func (m *Metrics) empty() bool {
+	return m == nil || len(m.Counters)+len(m.Gauges)+len(m.Histograms) == 0
+}
+
+// MergeMetrics 将src合并到dst并返回合并结果，dst为nil时返回src的拷贝
+// 计数和直方图相加，仪表取较新的src
+func MergeMetrics(dst, src *Metrics) *Metrics {
+	if src.empty() {
+		return dst
+	}
+	if dst == nil {
+		dst = &Metrics{}
+	}
+	for k, v := range src.Counters {
+		if dst.Counters == nil {
+			dst.Counters = make(map[string]int64, len(src.Counters))
+		}
+		dst.Counters[k] += v
+	}
+	for k, v := range src.Gauges {
+		if dst.Gauges == nil {
+			dst.Gauges = make(map[string]float64, len(src.Gauges))
+		}
+		dst.Gauges[k] = v
+	}
+	for k, v := range src.Histograms {
+		if dst.Histograms == nil {
+			dst.Histograms = make(map[string]*hdrhistogram.Histogram, len(src.Histograms))
+		}
+		dst.Histograms[k] = MergeHistogram(dst.Histograms[k], v)
+	}
+	return dst
+}
+
+// combineGauges 合并同一时间段内多个来源的仪表，各来源的值相加
+func combineGauges(dst, src *Metrics) map[string]float64 {
+	var res map[string]float64
+	for _, m := range []*Metrics{dst, src} {
+		if m == nil {
+			continue
+		}
+		for k, v := range m.Gauges {
+			if res == nil {
+				res = make(map[string]float64)
+			}
+			res[k] += v
+		}
+	}
+	return res
+}
+
+// MetricSummary 一个自定义指标的汇总
+type MetricSummary struct {
+	Name string
+	Kind string
+	// Value 计数为总数，仪表为最后一次记录的值，直方图为记录的个数
+	Value float64
+	// 以下只有直方图有
+	Min         int64        `json:",omitempty"`
+	Max         int64        `json:",omitempty"`
+	Mean        float64      `json:",omitempty"`
+	Percentiles []Percentile `json:",omitempty"`
+}
+
+// Field 取出汇总中的值，计数和仪表只有 value，直方图支持 count、min、max、mean 和 p99 这样的分位数
+func (s *MetricSummary) Field(field string) (float64, bool) {
+	if s.Kind != MetricHistogram {
+		return s.Value, field == "" || field == "value"
+	}
+	switch field {
+	case "", "count":
+		return s.Value, true
+	case "min":
+		return float64(s.Min), true
+	case "max":
+		return float64(s.Max), true
+	case "mean":
+		return s.Mean, true
+	}
+	for _, p := range s.Percentiles {
+		if PercentileName(p.Percentile) == field {
+			return float64(p.Value), true
+		}
+	}
+	return 0, false
+}
+
+// Summary 计算自定义指标的汇总，按类型和名称排序，没有指标时返回nil
+func (m *Metrics) Summary(percentiles []float64) []MetricSummary {
+	if m.empty() {
+		return nil
+	}
+	res := make([]MetricSummary, 0, len(m.Counters)+len(m.Gauges)+len(m.Histograms))
+	for _, k := range sortedKeys(m.Counters) {
+		res = append(res, MetricSummary{Name: k, Kind: MetricCounter, Value: float64(m.Counters[k])})
+	}
+	for _, k := range sortedKeys(m.Gauges) {
+		res = append(res, MetricSummary{Name: k, Kind: MetricGauge, Value: m.Gauges[k]})
+	}
+	for _, k := range sortedKeys(m.Histograms) {
+		h := m.Histograms[k]
+		s := MetricSummary{Name: k, Kind: MetricHistogram, Value: float64(h.TotalCount())}
+		if h.TotalCount() > 0 {
+			s.Min, s.Max, s.Mean = h.Min(), h.Max(), h.Mean()
+			for _, p := range percentiles {
+				s.Percentiles = append(s.Percentiles, Percentile{Percentile: p, Value: h.ValueAtPercentile(p)})
+			}
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String 汇总的文本形式，例如 "hits: 120"、"batch_size: n=20 mean=8.50 p99=16"
+func (s *MetricSummary) String() string {
+	if s.Kind != MetricHistogram {
+		return s.Name + ": " + strconv.FormatFloat(s.Value, 'f', -1, 64)
+	}
+	if s.Value == 0 {
+		return s.Name + ": n=0"
+	}
+	parts := []string{fmt.Sprintf("%s: n=%.0f min=%d mean=%.2f", s.Name, s.Value, s.Min, s.Mean)}
+	for _, p := range s.Percentiles {
+		parts = append(parts, fmt.Sprintf("%s=%d", PercentileName(p.Percentile), p.Value))
+	}
+	if len(s.Percentiles) == 0 || s.Percentiles[len(s.Percentiles)-1].Percentile != 100 {
+		parts = append(parts, fmt.Sprintf("max=%d", s.Max))
+	}
+	return strings.Join(parts, " ")
+}
+
+// LogMetrics 打印自定义指标的汇总
+func LogMetrics(prefix string, ms []MetricSummary) {
+	if len(ms) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(ms))
+	for i := range ms {
+		parts = append(parts, ms[i].String())
+	}
+	logger.Info("%s%s", prefix, strings.Join(parts, " | "))
+}
+
+// MetricRecorder 并发安全地记录自定义指标，同时累计区间内和整个压测的值
+type MetricRecorder struct {
+	mu       sync.Mutex
+	interval Metrics
+	total    Metrics
+	warned   bool
+}
+
+// admit 判断是否可以记录该名称，超过 maxMetrics 个名称时告警一次
+func admit[V any](r *MetricRecorder, total map[string]V, name string) bool {
+	if _, ok := total[name]; ok || len(total) < maxMetrics {
+		return true
+	}
+	if !r.warned {
+		r.warned = true
+		logger.Warning("Too many custom metrics, %s and later new names are dropped", name)
+	}
+	return false
+}
+
+// Count 计数增加delta
+func (r *MetricRecorder) Count(name string, delta int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.total.Counters == nil {
+		r.total.Counters = make(map[string]int64)
+		r.interval.Counters = make(map[string]int64)
+	}
+	if !admit(r, r.total.Counters, name) {
+		return
+	}
+	r.total.Counters[name] += delta
+	r.interval.Counters[name] += delta
+}
+
+// Gauge 设置仪表的当前值
+func (r *MetricRecorder) Gauge(name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.total.Gauges == nil {
+		r.total.Gauges = make(map[string]float64)
+		r.interval.Gauges = make(map[string]float64)
+	}
+	if !admit(r, r.total.Gauges, name) {
+		return
+	}
+	r.total.Gauges[name] = value
+	r.interval.Gauges[name] = value
+}
+
+// Observe 在直方图中记录一个值
+func (r *MetricRecorder) Observe(name string, value int64) {
+	if value < 0 || value > MetricHighest {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.total.Histograms == nil {
+		r.total.Histograms = make(map[string]*hdrhistogram.Histogram)
+		r.interval.Histograms = make(map[string]*hdrhistogram.Histogram)
+	}
+	if !admit(r, r.total.Histograms, name) {
+		return
+	}
+	for _, m := range []map[string]*hdrhistogram.Histogram{r.total.Histograms, r.interval.Histograms} {
+		h := m[name]
+		if h == nil {
+			h = NewMetricHistogram()
+			m[name] = h
+		}
+		_ = h.RecordValue(value)
+	}
+}
+
+// Interval 返回区间内记录的指标并开始新的区间，区间内没有记录时返回nil
+// 仪表总是带上最后一次记录的值，因此记录过仪表后每个区间都会返回
+func (r *MetricRecorder) Interval() *Metrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.interval.empty() {
+		return nil
+	}
+	res := r.interval
+	r.interval = Metrics{}
+	if r.total.Counters != nil {
+		r.interval.Counters = make(map[string]int64)
+	}
+	if r.total.Gauges != nil {
+		// 仪表沿用上次记录的值，区间内没有重新设置的仪表不会从图表中消失
+		r.interval.Gauges = make(map[string]float64, len(r.total.Gauges))
+		for k, v := range r.total.Gauges {
+			r.interval.Gauges[k] = v
+		}
+	}
+	if r.total.Histograms != nil {
+		r.interval.Histograms = make(map[string]*hdrhistogram.Histogram)
+	}
+	return &res
+}
+
+// Total 返回自上次Reset以来记录的指标的拷贝，没有记录时返回nil
+func (r *MetricRecorder) Total() *Metrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.total.empty() {
+		return nil
+	}
+	cp := r.total
+	return MergeMetrics(nil, &cp)
+}
+
+// Reset 清空所有指标
+func (r *MetricRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interval = Metrics{}
+	r.total = Metrics{}
+	r.warned = false
+}
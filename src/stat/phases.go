@@ -0,0 +1,203 @@
+package stat
+
+import (
+	"fmt"
+	"perform-cli-framework-go/src/logger"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+)
+
+// 请求的阶段，单位均为微秒
+const (
+	// PhaseDNS 域名解析
+	PhaseDNS = "dns"
+	// PhaseConnect 建立TCP连接
+	PhaseConnect = "connect"
+	// PhaseTLS TLS握手
+	PhaseTLS = "tls"
+	// PhaseTTFB 请求发送完成到收到响应的第一个字节，即服务端的处理时间加网络往返
+	PhaseTTFB = "ttfb"
+	// PhaseTransfer 收到第一个字节到读完响应
+	PhaseTransfer = "transfer"
+)
+
+// phaseOrder 输出时内置阶段的顺序，工作器自定义的阶段按名称排在后面
+var phaseOrder = []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseTTFB, PhaseTransfer}
+
+const (
+	// maxPhases 最多的阶段个数，超过后新的阶段不再记录
+	maxPhases = 16
+	// phaseSigFigs 阶段耗时直方图的有效位数
+	phaseSigFigs = 3
+)
+
+// MergePhases 将src中各阶段的直方图合并到dst并返回合并结果，dst为nil时返回src的拷贝
+func MergePhases(dst, src map[string]*hdrhistogram.Histogram) map[string]*hdrhistogram.Histogram {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]*hdrhistogram.Histogram, len(src))
+	}
+	for k, v := range src {
+		dst[k] = MergeHistogram(dst[k], v)
+	}
+	return dst
+}
+
+// PhaseSummary 一个阶段耗时的汇总，单位微秒
+type PhaseSummary struct {
+	Phase       string
+	Count       int64
+	Min         int64
+	Max         int64
+	Mean        float64
+	Percentiles []Percentile
+}
+
+// PhaseSummaries 计算各阶段耗时的汇总，内置阶段按请求的先后排列，没有记录时返回nil
+func PhaseSummaries(phases map[string]*hdrhistogram.Histogram, percentiles []float64) []PhaseSummary {
+	if len(phases) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(phases))
+	for k := range phases {
+		names = append(names, k)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		ia, ib := slices.Index(phaseOrder, names[a]), slices.Index(phaseOrder, names[b])
+		if ia < 0 {
+			ia = len(phaseOrder)
+		}
+		if ib < 0 {
+			ib = len(phaseOrder)
+		}
+		if ia != ib {
+			return ia < ib
+		}
+		return names[a] < names[b]
+	})
+	res := make([]PhaseSummary, 0, len(names))
+	for _, k := range names {
+		h := phases[k]
+		if h.TotalCount() == 0 {
+			continue
+		}
+		s := PhaseSummary{Phase: k, Count: h.TotalCount(), Min: h.Min(), Max: h.Max(), Mean: h.Mean()}
+		for _, p := range percentiles {
+			s.Percentiles = append(s.Percentiles, Percentile{Percentile: p, Value: h.ValueAtPercentile(p)})
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+// Field 取出汇总中的值，支持 count、min、max、mean 和 p99 这样的分位数
+func (s *PhaseSummary) Field(field string) (float64, bool) {
+	switch field {
+	case "count":
+		return float64(s.Count), true
+	case "min":
+		return float64(s.Min), true
+	case "max":
+		return float64(s.Max), true
+	case "mean":
+		return s.Mean, true
+	}
+	for _, p := range s.Percentiles {
+		if PercentileName(p.Percentile) == field {
+			return float64(p.Value), true
+		}
+	}
+	return 0, false
+}
+
+// String 汇总的文本形式，例如 "dns: n=20 mean=1.20ms p99=3.10ms max=3.20ms"
+func (s *PhaseSummary) String() string {
+	parts := []string{fmt.Sprintf("%s: n=%d mean=%s", s.Phase, s.Count, FormatLatency(int64(s.Mean)))}
+	for _, p := range s.Percentiles {
+		parts = append(parts, PercentileName(p.Percentile)+"="+FormatLatency(p.Value))
+	}
+	if len(s.Percentiles) == 0 || s.Percentiles[len(s.Percentiles)-1].Percentile != 100 {
+		parts = append(parts, "max="+FormatLatency(s.Max))
+	}
+	return strings.Join(parts, " ")
+}
+
+// LogPhases 打印各阶段耗时的汇总
+func LogPhases(prefix string, ps []PhaseSummary) {
+	if len(ps) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(ps))
+	for i := range ps {
+		parts = append(parts, ps[i].String())
+	}
+	logger.Info("%s%s", prefix, strings.Join(parts, " | "))
+}
+
+// PhaseRecorder 并发安全地记录请求各阶段的耗时，同时累计区间内和整个压测的直方图
+type PhaseRecorder struct {
+	mu       sync.Mutex
+	highest  int64
+	interval map[string]*hdrhistogram.Histogram
+	total    map[string]*hdrhistogram.Histogram
+}
+
+// NewPhaseRecorder 创建阶段耗时的记录，highest为可以记录的最大耗时(us)，超过的按最大值记录
+func NewPhaseRecorder(highest int64) *PhaseRecorder {
+	return &PhaseRecorder{highest: highest}
+}
+
+// Record 记录一次请求在该阶段的耗时(us)
+func (r *PhaseRecorder) Record(phase string, us int64) {
+	us = min(max(us, 0), r.highest)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.total == nil {
+		r.total = make(map[string]*hdrhistogram.Histogram)
+		r.interval = make(map[string]*hdrhistogram.Histogram)
+	}
+	if _, ok := r.total[phase]; !ok && len(r.total) >= maxPhases {
+		return
+	}
+	for _, m := range []map[string]*hdrhistogram.Histogram{r.total, r.interval} {
+		h := m[phase]
+		if h == nil {
+			h = hdrhistogram.New(1, r.highest, phaseSigFigs)
+			m[phase] = h
+		}
+		_ = h.RecordValue(us)
+	}
+}
+
+// Interval 返回区间内各阶段的直方图并开始新的区间，区间内没有记录时返回nil
+func (r *PhaseRecorder) Interval() map[string]*hdrhistogram.Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.interval) == 0 {
+		return nil
+	}
+	res := r.interval
+	r.interval = make(map[string]*hdrhistogram.Histogram, len(res))
+	return res
+}
+
+// Total 返回自上次Reset以来各阶段直方图的拷贝
+func (r *PhaseRecorder) Total() map[string]*hdrhistogram.Histogram {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return MergePhases(nil, r.total)
+}
+
+// Reset 清空所有阶段
+func (r *PhaseRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interval = nil
+	r.total = nil
+}
@@ -0,0 +1,71 @@
+package stat
+
+// IntervalSample 一个统计区间的摘要，随压测结果保存，对比两次压测时用于统计检验
+type IntervalSample struct {
+	// Start 区间的开始时间，unix时间戳(ms)
+	Start int64
+	// Durations 区间内实际发压的时长，不含暂停，单位微秒
+	Durations   int64
+	Requests    int64
+	Errors      int64
+	Mean        float64
+	Max         int64
+	Percentiles []Percentile
+}
+
+// Sample 计算区间的摘要，没有直方图时返回nil
+func (i *IntervalStatistic) Sample(percentiles []float64) *IntervalSample {
+	h := i.Histogram
+	if h == nil {
+		return nil
+	}
+	// ErrorTotal 是累计值，区间内的错误数按错误分类求和
+	errs := int64(0)
+	for _, v := range i.Errors {
+		errs += v
+	}
+	s := &IntervalSample{
+		Start:     h.StartTimeMs(),
+		Durations: i.Durations - i.PausedDurations,
+		Requests:  h.TotalCount() + errs,
+		Errors:    errs,
+		Mean:      h.Mean(),
+		Max:       h.Max(),
+	}
+	for _, p := range percentiles {
+		s.Percentiles = append(s.Percentiles, Percentile{Percentile: p, Value: h.ValueAtPercentile(p)})
+	}
+	return s
+}
+
+// Value 取出区间的指标，支持 rps、error_rate、mean、max 和 p99 这样的分位数，区间内没有该指标时返回false
+func (s *IntervalSample) Value(metric string) (float64, bool) {
+	switch metric {
+	case "rps":
+		if s.Durations <= 0 {
+			return 0, false
+		}
+		return float64(s.Requests) / (float64(s.Durations) / 1000 / 1000), true
+	case "error_rate":
+		if s.Requests == 0 {
+			return 0, false
+		}
+		return float64(s.Errors) / float64(s.Requests), true
+	}
+	// 时延指标只在有成功请求时有意义
+	if s.Requests == s.Errors {
+		return 0, false
+	}
+	switch metric {
+	case "mean":
+		return s.Mean, true
+	case "max":
+		return float64(s.Max), true
+	}
+	for _, p := range s.Percentiles {
+		if PercentileName(p.Percentile) == metric {
+			return float64(p.Value), true
+		}
+	}
+	return 0, false
+}
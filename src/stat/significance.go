@@ -0,0 +1,59 @@
+package stat
+
+import (
+	"math"
+	"sort"
+)
+
+// MinTestSamples 每组至少需要的样本数，少于该数量时不做统计检验
+const MinTestSamples = 5
+
+// MannWhitney 双侧 Mann-Whitney U 检验，返回两组样本来自同一分布的p值
+// 区间样本通常不服从正态分布且存在毛刺，使用基于秩的非参数检验，样本数足够时以带结修正和连续性修正的正态分布近似
+// 任一组样本数少于 MinTestSamples 时返回NaN
+func MannWhitney(a, b []float64) float64 {
+	n1, n2 := len(a), len(b)
+	if n1 < MinTestSamples || n2 < MinTestSamples {
+		return math.NaN()
+	}
+	type item struct {
+		v     float64
+		first bool
+	}
+	all := make([]item, 0, n1+n2)
+	for _, v := range a {
+		all = append(all, item{v: v, first: true})
+	}
+	for _, v := range b {
+		all = append(all, item{v: v})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].v < all[j].v
+	})
+	n := float64(n1 + n2)
+	r1, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		// 相同的值取平均秩
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].first {
+				r1 += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+	u := r1 - float64(n1)*float64(n1+1)/2
+	mu := float64(n1) * float64(n2) / 2
+	sigma := math.Sqrt(float64(n1) * float64(n2) / 12 * ((n + 1) - ties/(n*(n-1))))
+	if sigma == 0 {
+		return 1
+	}
+	z := math.Max(math.Abs(u-mu)-0.5, 0) / sigma
+	return math.Erfc(z / math.Sqrt2)
+}
@@ -2,8 +2,13 @@ package stat
 
 import (
 	"fmt"
+	"math"
 	"perform-cli-framework-go/src/logger"
 	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
 )
 
 type Record struct {
@@ -18,11 +23,113 @@ type IntervalStatistic struct {
 	SendBytes  int64
 	RecvBytes  int64
 	Records    []Record
+	// PausedDurations 该区间内处于暂停状态的时长，单位微秒
+	PausedDurations int64
+	// Paused 取统计时压测是否处于暂停状态
+	Paused bool
+	// Errors 区间内按错误信息分类的错误数
+	Errors map[string]int64
+	// Generator 压测机自身的资源使用，压测机跟不上目标速率时Saturated为true
+	Generator *GeneratorStats
+	// Histogram 区间时延直方图，Records 只有桶的下标，跨执行器合并需要使用该直方图
+	Histogram *hdrhistogram.Histogram `json:"-"`
+	// Metrics 工作器记录的自定义指标，没有记录时为nil
+	Metrics *Metrics `json:",omitempty"`
+	// Phases 按阶段（dns、connect、tls、ttfb、transfer 等）的耗时直方图，单位微秒
+	Phases map[string]*hdrhistogram.Histogram `json:"-"`
+}
+
+// Merge 将src合并到dst并返回合并结果，dst为nil时返回src的拷贝
+// SendTotal/ErrorTotal 是累计值取较新的src，其余区间值相加
+func Merge(dst, src *IntervalStatistic) *IntervalStatistic {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		cp := *src
+		cp.Records = append([]Record(nil), src.Records...)
+		cp.Histogram = CopyHistogram(src.Histogram)
+		cp.Errors = mergeErrors(nil, src.Errors)
+		cp.Generator = MergeGenerator(nil, src.Generator)
+		cp.Metrics = MergeMetrics(nil, src.Metrics)
+		cp.Phases = MergePhases(nil, src.Phases)
+		return &cp
+	}
+	dst.SendTotal = src.SendTotal
+	dst.ErrorTotal = src.ErrorTotal
+	dst.Paused = src.Paused
+	dst.Durations += src.Durations
+	dst.SendBytes += src.SendBytes
+	dst.RecvBytes += src.RecvBytes
+	dst.PausedDurations += src.PausedDurations
+	dst.Histogram = MergeHistogram(dst.Histogram, src.Histogram)
+	dst.Errors = mergeErrors(dst.Errors, src.Errors)
+	dst.Generator = MergeGenerator(dst.Generator, src.Generator)
+	dst.Metrics = MergeMetrics(dst.Metrics, src.Metrics)
+	dst.Phases = MergePhases(dst.Phases, src.Phases)
+	counts := make(map[int64]int64, len(dst.Records)+len(src.Records))
+	for _, r := range dst.Records {
+		counts[r.Key] += r.Value
+	}
+	for _, r := range src.Records {
+		counts[r.Key] += r.Value
+	}
+	records := make([]Record, 0, len(counts))
+	for k, v := range counts {
+		records = append(records, Record{Key: k, Value: v})
+	}
+	sort.Slice(records, func(a, b int) bool {
+		return records[a].Key < records[b].Key
+	})
+	dst.Records = records
+	return dst
+}
+
+func mergeErrors(dst, src map[string]int64) map[string]int64 {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]int64, len(src))
+	}
+	for k, v := range src {
+		dst[k] += v
+	}
+	return dst
 }
 
-// LogSelf 打印统计数据
-func (i *IntervalStatistic) LogSelf() {
+// Combine 合并同一时间段内多个来源(例如多个执行器)的统计并返回合并结果，dst为nil时返回src的拷贝
+// 与Merge不同，累计值相加，区间时长取最长的一个，自定义指标的仪表相加
+func Combine(dst, src *IntervalStatistic) *IntervalStatistic {
+	if src == nil || dst == nil {
+		return Merge(dst, src)
+	}
+	sendTotal := dst.SendTotal + src.SendTotal
+	errorTotal := dst.ErrorTotal + src.ErrorTotal
+	durations := max(dst.Durations, src.Durations)
+	pausedDurations := max(dst.PausedDurations, src.PausedDurations)
+	paused := dst.Paused || src.Paused
+	gauges := combineGauges(dst.Metrics, src.Metrics)
+	dst = Merge(dst, src)
+	if dst.Metrics != nil {
+		dst.Metrics.Gauges = gauges
+	}
+	dst.SendTotal = sendTotal
+	dst.ErrorTotal = errorTotal
+	dst.Durations = durations
+	dst.PausedDurations = pausedDurations
+	dst.Paused = paused
+	return dst
+}
+
+// LogSelf 打印统计数据，percentiles为需要输出的分位数
+func (i *IntervalStatistic) LogSelf(percentiles []float64) {
+	defer LogMetrics("[Metrics] ", i.Metrics.Summary(percentiles))
+	defer LogPhases("[Phases] ", PhaseSummaries(i.Phases, percentiles))
 	if len(i.Records) == 0 {
+		if i.Paused {
+			logger.Info("[Stats] Paused for %d s", i.PausedDurations/(1000*1000))
+		}
 		return
 	}
 	// 1. 按时延（Key）从小到大排序
@@ -43,12 +150,16 @@ func (i *IntervalStatistic) LogSelf() {
 	if i.Durations > 0 {
 		qps = float64(intervalTotal) / (float64(i.Durations) / (1000 * 1000))
 	}
+	// 区间内有暂停时按实际发压的时长计算QPS
+	if i.PausedDurations > 0 && i.Durations > i.PausedDurations {
+		qps = float64(intervalTotal) / (float64(i.Durations-i.PausedDurations) / (1000 * 1000))
+	}
 	// 4. 错误率
 	errorRate := 0.0
 	if i.SendTotal > 0 {
 		errorRate = float64(i.ErrorTotal) / float64(i.SendTotal) * 100
 	}
-	// 5. 计算分位数（P99/P95/P90）
+	// 5. 计算分位数
 	calculatePercentile := func(percentile float64) int64 {
 		if total == 0 {
 			return 0
@@ -63,27 +174,41 @@ func (i *IntervalStatistic) LogSelf() {
 		}
 		return i.Records[len(i.Records)-1].Key // 兜底返回最大值
 	}
-	p99 := calculatePercentile(0.99)
-	p95 := calculatePercentile(0.95)
-	p90 := calculatePercentile(0.90)
-	// 6. 格式化输出
+	ps := make([]string, 0, len(percentiles))
+	for _, p := range percentiles {
+		v := calculatePercentile(p / 100)
+		// 有直方图时使用直方图计算，Records的Key是桶下标
+		if i.Histogram != nil {
+			v = i.Histogram.ValueAtPercentile(p)
+		}
+		ps = append(ps, fmt.Sprintf("%s: %s", strings.ToUpper(PercentileName(p)), FormatLatency(v)))
+	}
+	// 6. 格式化输出，区间可能略短于1s，按浮点数计算秒数
+	seconds := max(float64(i.Durations)/(1000*1000), 1e-6)
 	logger.Info(
-		"[Stats] QPS: %.2f | Error: %.2f%% | Send: %s /s | Recv: %s /s | Latency (us) - P99: %d, P95: %d, P90: %d",
+		"[Stats] QPS: %.2f | Error: %.2f%% | Send: %s /s | Recv: %s /s | Latency - %s",
 		qps,
 		errorRate,
-		formatBytes(float64(i.SendBytes)/float64((i.Durations)/(1000*1000))),
-		formatBytes(float64(i.RecvBytes)/float64((i.Durations)/(1000*1000))),
-		p99,
-		p95,
-		p90,
+		formatBytes(float64(i.SendBytes)/seconds),
+		formatBytes(float64(i.RecvBytes)/seconds),
+		strings.Join(ps, ", "),
 	)
+	if i.PausedDurations > 0 {
+		logger.Info("[Stats] Paused %d ms in this interval", i.PausedDurations/1000)
+	}
+	if i.Generator != nil {
+		i.Generator.LogSelf()
+	}
 }
 
 // 辅助函数：格式化字节为易读单位（KB/MB/GB）
 func formatBytes(b float64) string {
 	const unit = 1024
+	if math.IsInf(b, 0) || math.IsNaN(b) {
+		return "-"
+	}
 	if b < unit {
-		return fmt.Sprintf("%fB", b)
+		return fmt.Sprintf("%.0fB", b)
 	}
 	div, exp := int64(unit), 0
 	for n := b / unit; n >= unit; n /= unit {
@@ -93,10 +218,117 @@ func formatBytes(b float64) string {
 	return fmt.Sprintf("%.1f%cB", b/float64(div), "KMGTPE"[exp])
 }
 
+// FormatLatency 按大小选择单位格式化时延，us为微秒
+func FormatLatency(us int64) string {
+	switch {
+	case us < 1000:
+		return fmt.Sprintf("%dµs", us)
+	case us < 1000*1000:
+		return fmt.Sprintf("%.2fms", float64(us)/1000)
+	default:
+		return fmt.Sprintf("%.2fs", float64(us)/1000/1000)
+	}
+}
+
+// Percentile 分位数及对应的时延(us)
+type Percentile struct {
+	Percentile float64
+	Value      int64
+}
+
+// Summary 整个压测周期的汇总统计，时延单位为微秒
+type Summary struct {
+	SendTotal   int64
+	ErrorTotal  int64
+	Min         int64
+	Max         int64
+	Mean        float64
+	StdDev      float64
+	Percentiles []Percentile
+	// Metrics 工作器记录的自定义指标的汇总
+	Metrics []MetricSummary `json:",omitempty"`
+	// Phases 请求各阶段耗时的汇总
+	Phases []PhaseSummary `json:",omitempty"`
+}
+
+// SummaryPercentiles 汇总统计默认输出的分位数
+var SummaryPercentiles = []float64{50, 90, 95, 99}
+
+// PercentileName 分位数的名称，例如 p99.9，100 为 max
+func PercentileName(p float64) string {
+	if p == 100 {
+		return "max"
+	}
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// ParsePercentiles 解析 50,90,p99.9,max 这样的分位数列表
+func ParsePercentiles(s string) ([]float64, error) {
+	res := make([]float64, 0)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		p, err := ParsePercentile(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// ParsePercentile 解析单个分位数，支持 99.9、p99.9 和 max
+func ParsePercentile(v string) (float64, error) {
+	if v == "max" {
+		return 100, nil
+	}
+	p, err := strconv.ParseFloat(strings.TrimPrefix(v, "p"), 64)
+	if err != nil || p <= 0 || p > 100 {
+		return 0, fmt.Errorf("invalid percentile %q, want e.g. 50, p99.9 or max", v)
+	}
+	return p, nil
+}
+
+// LogSelf 打印汇总统计
+func (s *Summary) LogSelf() {
+	logger.Info("Latency Statistics:")
+	logger.Info("  Min      : %s", FormatLatency(s.Min))
+	logger.Info("  Max      : %s", FormatLatency(s.Max))
+	logger.Info("  Mean     : %s", FormatLatency(int64(s.Mean)))
+	logger.Info("  StdDev   : %s", FormatLatency(int64(s.StdDev)))
+	logger.Info("Percentiles:")
+	for _, p := range s.Percentiles {
+		logger.Info("  %-7s: %s", strings.ToUpper(PercentileName(p.Percentile)), FormatLatency(p.Value))
+	}
+	if len(s.Phases) > 0 {
+		logger.Info("Phases:")
+	}
+	for i := range s.Phases {
+		logger.Info("  %s", s.Phases[i].String())
+	}
+	if len(s.Metrics) > 0 {
+		logger.Info("Metrics:")
+	}
+	for i := range s.Metrics {
+		logger.Info("  %s", s.Metrics[i].String())
+	}
+}
+
 type Stater interface {
 	AddLatency(latency int64)
 	RecordBytes(value int64, isSend bool)
 	RecordErr(errMsg string)
+	// Count 自定义计数指标增加delta
+	Count(name string, delta int64)
+	// Gauge 设置自定义仪表指标的当前值
+	Gauge(name string, value float64)
+	// Observe 在自定义直方图指标中记录一个值
+	Observe(name string, value int64)
+	// RecordPhase 记录一次请求在某个阶段的耗时，单位微秒
+	RecordPhase(phase string, us int64)
 	Reset()
 	GetIntervalStatistic() *IntervalStatistic
+	GetSummary(percentiles []float64) *Summary
 }
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"perform-cli-framework-go/src/scenario"
+	"perform-cli-framework-go/src/stat"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// trendBarWidth 趋势图中最大值的柱长
+const trendBarWidth = 40
+
+// formatTime 格式化unix时间戳(us)
+func formatTime(us int64) string {
+	return time.UnixMicro(us).Format("2006-01-02 15:04:05")
+}
+
+// shortSHA git 版本只显示前7位
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// formatTags 按键排序输出 k=v,k2=v2
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, k+"="+tags[k])
+	}
+	return strings.Join(kvs, ",")
+}
+
+// FormatMetric 按指标的单位格式化，时延为us，error_rate为比例
+func FormatMetric(metric string, v float64) string {
+	switch {
+	case metric == "error_rate":
+		return fmt.Sprintf("%.2f%%", v*100)
+	case metric == "requests", metric == "rps", metric == "errors":
+		return strconv.FormatInt(int64(v), 10)
+	}
+	return stat.FormatLatency(int64(v))
+}
+
+// metric 取出压测的指标，没有结果或没有该指标时返回"-"
+func metric(e *Entry, name string) string {
+	v, err := scenario.MetricValue(name, e.Report.Run.Result)
+	if err != nil {
+		return "-"
+	}
+	return FormatMetric(name, v)
+}
+
+// PrintList 以表格形式打印压测列表
+func PrintList(w io.Writer, entries []*Entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ID\tSTARTED\tSTATE\tSCENARIO\tWORKER\tDURATION\tRPS\tERRORS\tP99\tPASS\tVERSION\tGIT\tTAGS")
+	for _, e := range entries {
+		run := e.Report.Run
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s x %d\t%ds\t%s\t%s\t%s\t%v\t%s\t%s\t%s\n",
+			run.ID, formatTime(run.StartAt), run.State, e.Report.Scenario, run.Config.WorkerName, run.Config.Workers,
+			run.Result.Durations/1000/1000, metric(e, "rps"), metric(e, "error_rate"), metric(e, "p99"),
+			e.Report.Pass, e.Meta.TargetVersion, shortSHA(e.Meta.GitSHA), formatTags(e.Tags))
+	}
+	_ = tw.Flush()
+}
+
+// PrintEntry 打印压测的环境信息和报告
+func PrintEntry(w io.Writer, e *Entry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "Saved:\t%s\n", time.UnixMilli(e.Meta.SavedAt).Format("2006-01-02 15:04:05"))
+	_, _ = fmt.Fprintf(tw, "Host:\t%s\n", e.Meta.Host)
+	if e.Meta.GitSHA != "" {
+		_, _ = fmt.Fprintf(tw, "Git SHA:\t%s\n", e.Meta.GitSHA)
+	}
+	if e.Meta.TargetVersion != "" {
+		_, _ = fmt.Fprintf(tw, "Target version:\t%s\n", e.Meta.TargetVersion)
+	}
+	_ = tw.Flush()
+	// 报告中的标签替换为结果库中可能修改过的标签
+	rp := *e.Report
+	run := *rp.Run
+	run.Config.Tags = e.Tags
+	rp.Run = &run
+	rp.Print(w)
+}
+
+// PrintTrend 打印指标在多次压测间的变化，每次压测一行，以柱长表示指标的值
+func PrintTrend(w io.Writer, entries []*Entry, name string) {
+	values := make([]float64, len(entries))
+	ok := make([]bool, len(entries))
+	top := 0.0
+	for i, e := range entries {
+		v, err := scenario.MetricValue(name, e.Report.Run.Result)
+		if err != nil {
+			continue
+		}
+		values[i], ok[i] = v, true
+		top = max(top, v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "ID\tSTARTED\tVERSION\tGIT\t%s\tCHANGE\t\n", strings.ToUpper(name))
+	prev := -1
+	for i, e := range entries {
+		run := e.Report.Run
+		value, change, bar := "-", "", ""
+		if ok[i] {
+			value = FormatMetric(name, values[i])
+			if prev >= 0 && values[prev] != 0 {
+				change = fmt.Sprintf("%+.2f%%", (values[i]-values[prev])/values[prev]*100)
+			}
+			if top > 0 {
+				bar = strings.Repeat("█", max(int(values[i]/top*trendBarWidth), 1))
+			}
+			prev = i
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", run.ID, formatTime(run.StartAt),
+			e.Meta.TargetVersion, shortSHA(e.Meta.GitSHA), value, change, bar)
+	}
+	_ = tw.Flush()
+}
+
+// WriteCSV 以CSV输出压测列表，metrics为输出的指标列
+func WriteCSV(w io.Writer, entries []*Entry, metrics []string) error {
+	cw := csv.NewWriter(w)
+	header := []string{"id", "started", "state", "scenario", "worker", "workers", "duration_s", "pass", "target_version", "git_sha", "tags"}
+	header = append(header, metrics...)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		run := e.Report.Run
+		row := []string{
+			run.ID, time.UnixMicro(run.StartAt).Format(time.RFC3339), run.State.String(), e.Report.Scenario,
+			run.Config.WorkerName, strconv.FormatInt(run.Config.Workers, 10), strconv.FormatInt(run.Result.Durations/1000/1000, 10),
+			strconv.FormatBool(e.Report.Pass), e.Meta.TargetVersion, e.Meta.GitSHA, formatTags(e.Tags),
+		}
+		for _, m := range metrics {
+			v, err := scenario.MetricValue(m, run.Result)
+			if err != nil {
+				row = append(row, "")
+				continue
+			}
+			row = append(row, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
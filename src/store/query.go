@@ -0,0 +1,67 @@
+package store
+
+import (
+	"fmt"
+	"time"
+)
+
+// Query 结果库的查询条件，为空的条件不过滤
+type Query struct {
+	Scenario string
+	Worker   string
+	State    string
+	// Tags 需要全部匹配的标签
+	Tags map[string]string
+	// Since、Until 压测开始时间的范围，unix时间戳(us)
+	Since int64
+	Until int64
+	// Limit 只返回最近的Limit次压测
+	Limit int
+}
+
+// Match 压测是否满足查询条件
+func (q *Query) Match(e *Entry) bool {
+	run := e.Report.Run
+	switch {
+	case q.Scenario != "" && e.Report.Scenario != q.Scenario,
+		q.Worker != "" && run.Config.WorkerName != q.Worker,
+		q.State != "" && run.State.String() != q.State,
+		q.Since > 0 && run.StartAt < q.Since,
+		q.Until > 0 && run.StartAt >= q.Until:
+		return false
+	}
+	for k, v := range q.Tags {
+		if tv, ok := e.Tags[k]; !ok || tv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTime 解析查询的时间，可以写相对现在的时长 24h、7d，也可以写日期 2006-01-02 或 2006-01-02T15:04:05，返回unix时间戳(us)
+func ParseTime(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, ok := cutDays(s); ok {
+		return time.Now().Add(-time.Duration(n) * 24 * time.Hour).UnixMicro(), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(-d).UnixMicro(), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.RFC3339} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t.UnixMicro(), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid time %q, want e.g. 24h, 7d or 2006-01-02", s)
+}
+
+// cutDays 解析 7d 这样的天数
+func cutDays(s string) (int, bool) {
+	var n int
+	if _, err := fmt.Sscanf(s, "%dd", &n); err != nil || fmt.Sprintf("%dd", n) != s {
+		return 0, false
+	}
+	return n, true
+}
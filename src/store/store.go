@@ -0,0 +1,224 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"perform-cli-framework-go/src/scenario"
+	"perform-cli-framework-go/src/utils"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// DirEnv 结果库目录的环境变量，没有指定 -store 时使用
+const DirEnv = "PERFORM_STORE"
+
+// dbFile 结果库目录下的数据库文件
+const dbFile = "runs.db"
+
+// openTimeout 等待其他进程释放数据库文件锁的时长
+const openTimeout = 5 * time.Second
+
+var bucketRuns = []byte("runs")
+
+// ErrNotFound 结果库中没有该压测
+var ErrNotFound = errors.New("run not found")
+
+// Meta 保存压测时记录的环境信息
+type Meta struct {
+	// GitSHA 被测代码的版本，取自 -git-sha 参数或 GIT_SHA、GITHUB_SHA、CI_COMMIT_SHA 环境变量
+	GitSHA        string `json:"git_sha,omitempty"`
+	TargetVersion string `json:"target_version,omitempty"`
+	// Host 执行压测的实例，执行器为执行器名称
+	Host string `json:"host,omitempty"`
+	// SavedAt 保存的时间，unix时间戳(ms)
+	SavedAt int64 `json:"saved_at"`
+}
+
+// Entry 结果库中的一次压测
+type Entry struct {
+	Meta Meta `json:"meta"`
+	// Tags 压测的标签，初始为压测配置中的标签，可以通过 results tag 修改
+	Tags   map[string]string `json:"tags,omitempty"`
+	Report *scenario.Report  `json:"report"`
+}
+
+// ID 压测ID，也是结果库中的键
+func (e *Entry) ID() string {
+	return e.Report.Run.ID
+}
+
+// Store 基于 bbolt 的本地结果库，以压测ID为键保存 Entry 的json
+// 压测ID以毫秒时间戳开头，键的顺序即压测开始的顺序
+type Store struct {
+	db *bolt.DB
+}
+
+// Open 打开dir下的结果库，目录不存在时创建，readOnly时多个进程可以同时读取
+func Open(dir string, readOnly bool) (*Store, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("no result store, set -store or %s", DirEnv)
+	}
+	if !readOnly {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("create result store: %w", err)
+		}
+	}
+	path := filepath.Join(dir, dbFile)
+	if readOnly {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("open result store: %w", err)
+		}
+	}
+	db, err := bolt.Open(path, 0644, &bolt.Options{Timeout: openTimeout, ReadOnly: readOnly})
+	if err != nil {
+		return nil, fmt.Errorf("open result store %s: %w", path, err)
+	}
+	s := &Store{db: db}
+	if readOnly {
+		return s, nil
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketRuns)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put 保存压测，已经存在时覆盖
+func (s *Store) Put(e *Entry) error {
+	js, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRuns).Put([]byte(e.ID()), js)
+	})
+}
+
+// Get 按ID读取压测，不存在时返回ErrNotFound
+func (s *Store) Get(id string) (*Entry, error) {
+	var e *Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketRuns)
+		if b == nil {
+			return nil
+		}
+		v := b.Get([]byte(id))
+		if v == nil {
+			return nil
+		}
+		e = &Entry{}
+		return json.Unmarshal(v, e)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return e, nil
+}
+
+// Update 读取压测并用fn修改后保存
+func (s *Store) Update(id string, fn func(e *Entry) error) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketRuns)
+		v := b.Get([]byte(id))
+		if v == nil {
+			return fmt.Errorf("%w: %s", ErrNotFound, id)
+		}
+		e := &Entry{}
+		if err := json.Unmarshal(v, e); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+		js, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(id), js)
+	})
+}
+
+// Query 按条件查询压测，按开始时间从早到晚排列
+func (s *Store) Query(q Query) ([]*Entry, error) {
+	res := make([]*Entry, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketRuns)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			e := &Entry{}
+			if err := json.Unmarshal(v, e); err != nil {
+				return fmt.Errorf("decode run %s: %w", k, err)
+			}
+			if q.Match(e) {
+				res = append(res, e)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(res) > q.Limit {
+		res = res[len(res)-q.Limit:]
+	}
+	return res, nil
+}
+
+// Save 把压测报告连同环境信息保存到dir下的结果库
+func Save(dir string, rp *scenario.Report, gitSHA, targetVersion string) error {
+	if rp == nil || rp.Run == nil || rp.Run.Result == nil {
+		return fmt.Errorf("run has no result")
+	}
+	tags := make(map[string]string, len(rp.Run.Config.Tags))
+	for k, v := range rp.Run.Config.Tags {
+		tags[k] = v
+	}
+	if targetVersion == "" {
+		targetVersion = tags["target_version"]
+	}
+	s, err := Open(dir, false)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	return s.Put(&Entry{
+		Meta: Meta{
+			GitSHA:        gitSHA,
+			TargetVersion: targetVersion,
+			Host:          utils.Instance(),
+			SavedAt:       time.Now().UnixMilli(),
+		},
+		Tags:   tags,
+		Report: rp,
+	})
+}
+
+// GitSHAFromEnv 从CI的环境变量中取被测代码的版本，都没有设置时返回空
+func GitSHAFromEnv() string {
+	for _, k := range []string{"GIT_SHA", "GITHUB_SHA", "CI_COMMIT_SHA"} {
+		if v := os.Getenv(k); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package tracing
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fileExporter 每批span以一行 OTLP JSON(ExportTraceServiceRequest) 追加写入文件，
+// 与 collector 的 file exporter 格式一致，可以用 otlpjsonfile receiver 导入
+type fileExporter struct {
+	m sync.Mutex
+	f *os.File
+}
+
+func newFileExporter(path string) (*fileExporter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %w", err)
+	}
+	return &fileExporter{f: f}, nil
+}
+
+func (e *fileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	js, err := protojson.Marshal(toRequest(spans))
+	if err != nil {
+		return err
+	}
+	if js, err = hexIDs(js); err != nil {
+		return err
+	}
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.f == nil {
+		return os.ErrClosed
+	}
+	_, err = e.f.Write(append(js, '\n'))
+	return err
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if e.f == nil {
+		return nil
+	}
+	err := e.f.Close()
+	e.f = nil
+	return err
+}
+
+// idKeys OTLP JSON 中以十六进制而不是base64表示的字段
+var idKeys = map[string]bool{"traceId": true, "spanId": true, "parentSpanId": true}
+
+// hexIDs protojson 把bytes编码为base64，OTLP JSON 规定trace和span的ID使用十六进制
+func hexIDs(js []byte) ([]byte, error) {
+	var v interface{}
+	if err := json.Unmarshal(js, &v); err != nil {
+		return nil, err
+	}
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			for k, e := range t {
+				if s, ok := e.(string); ok && idKeys[k] {
+					if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+						t[k] = hex.EncodeToString(b)
+					}
+					continue
+				}
+				walk(e)
+			}
+		case []interface{}:
+			for _, e := range t {
+				walk(e)
+			}
+		}
+	}
+	walk(v)
+	return json.Marshal(v)
+}
+
+// toRequest 按资源和instrumentation scope分组转换成OTLP请求
+func toRequest(spans []sdktrace.ReadOnlySpan) *coltracepb.ExportTraceServiceRequest {
+	type scopeKey struct {
+		res   attribute.Distinct
+		scope instrumentation.Scope
+	}
+	resources := make(map[attribute.Distinct]*tracepb.ResourceSpans)
+	scopes := make(map[scopeKey]*tracepb.ScopeSpans)
+	req := &coltracepb.ExportTraceServiceRequest{}
+	for _, s := range spans {
+		res := s.Resource()
+		rk := res.Equivalent()
+		rs, ok := resources[rk]
+		if !ok {
+			rs = &tracepb.ResourceSpans{
+				Resource:  &resourcepb.Resource{Attributes: toAttributes(res.Attributes())},
+				SchemaUrl: res.SchemaURL(),
+			}
+			resources[rk] = rs
+			req.ResourceSpans = append(req.ResourceSpans, rs)
+		}
+		sk := scopeKey{res: rk, scope: s.InstrumentationScope()}
+		ss, ok := scopes[sk]
+		if !ok {
+			ss = &tracepb.ScopeSpans{
+				Scope:     &commonpb.InstrumentationScope{Name: sk.scope.Name, Version: sk.scope.Version},
+				SchemaUrl: sk.scope.SchemaURL,
+			}
+			scopes[sk] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+		ss.Spans = append(ss.Spans, toSpan(s))
+	}
+	return req
+}
+
+func toSpan(s sdktrace.ReadOnlySpan) *tracepb.Span {
+	sc := s.SpanContext()
+	tid, sid := sc.TraceID(), sc.SpanID()
+	span := &tracepb.Span{
+		TraceId:                tid[:],
+		SpanId:                 sid[:],
+		TraceState:             sc.TraceState().String(),
+		Name:                   s.Name(),
+		Kind:                   tracepb.Span_SpanKind(s.SpanKind()),
+		StartTimeUnixNano:      uint64(s.StartTime().UnixNano()),
+		EndTimeUnixNano:        uint64(s.EndTime().UnixNano()),
+		Attributes:             toAttributes(s.Attributes()),
+		DroppedAttributesCount: uint32(s.DroppedAttributes()),
+		DroppedEventsCount:     uint32(s.DroppedEvents()),
+		DroppedLinksCount:      uint32(s.DroppedLinks()),
+		Status:                 &tracepb.Status{Message: s.Status().Description},
+	}
+	if s.SpanKind() == trace.SpanKindUnspecified {
+		span.Kind = tracepb.Span_SPAN_KIND_INTERNAL
+	}
+	switch s.Status().Code {
+	case codes.Ok:
+		span.Status.Code = tracepb.Status_STATUS_CODE_OK
+	case codes.Error:
+		span.Status.Code = tracepb.Status_STATUS_CODE_ERROR
+	}
+	if p := s.Parent(); p.IsValid() {
+		psid := p.SpanID()
+		span.ParentSpanId = psid[:]
+	}
+	for _, ev := range s.Events() {
+		span.Events = append(span.Events, &tracepb.Span_Event{
+			Name:                   ev.Name,
+			TimeUnixNano:           uint64(ev.Time.UnixNano()),
+			Attributes:             toAttributes(ev.Attributes),
+			DroppedAttributesCount: uint32(ev.DroppedAttributeCount),
+		})
+	}
+	for _, l := range s.Links() {
+		ltid, lsid := l.SpanContext.TraceID(), l.SpanContext.SpanID()
+		span.Links = append(span.Links, &tracepb.Span_Link{
+			TraceId:                ltid[:],
+			SpanId:                 lsid[:],
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             toAttributes(l.Attributes),
+			DroppedAttributesCount: uint32(l.DroppedAttributeCount),
+		})
+	}
+	return span
+}
+
+func toAttributes(attrs []attribute.KeyValue) []*commonpb.KeyValue {
+	res := make([]*commonpb.KeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		res = append(res, &commonpb.KeyValue{Key: string(kv.Key), Value: toValue(kv.Value)})
+	}
+	return res
+}
+
+func toValue(v attribute.Value) *commonpb.AnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v.AsBool()}}
+	case attribute.INT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: v.AsInt64()}}
+	case attribute.FLOAT64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v.AsFloat64()}}
+	case attribute.BOOLSLICE:
+		return arrayValue(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return arrayValue(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return arrayValue(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return arrayValue(v.AsStringSlice(), attribute.StringValue)
+	default:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v.Emit()}}
+	}
+}
+
+func arrayValue[T any](vs []T, value func(T) attribute.Value) *commonpb.AnyValue {
+	arr := &commonpb.ArrayValue{}
+	for _, v := range vs {
+		arr.Values = append(arr.Values, toValue(value(v)))
+	}
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: arr}}
+}
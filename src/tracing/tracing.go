@@ -0,0 +1,163 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/utils"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 压测请求span上的属性
+const (
+	AttrRunID     = attribute.Key("perform.run_id")
+	AttrWorker    = attribute.Key("perform.worker")
+	AttrExecutor  = attribute.Key("perform.executor")
+	AttrOperation = attribute.Key("perform.operation")
+)
+
+const (
+	serviceName = "perform-cli-framework-go"
+	// DefaultSample 未指定采样比例时使用的比例
+	DefaultSample = 0.01
+	// maxQueueSize 等待导出的span上限，超过时丢弃新的span，不阻塞发压
+	maxQueueSize = 8192
+)
+
+// tracer 当前压测使用的tracer，为nil时不开启追踪
+type tracer struct {
+	t        trace.Tracer
+	sample   float64
+	provider *sdktrace.TracerProvider
+}
+
+var (
+	current atomic.Pointer[tracer]
+	// propagator W3C trace context
+	propagator = propagation.TraceContext{}
+	m          sync.Mutex
+)
+
+// Start 按配置开启追踪，tc为nil或Endpoint为空时不开启，返回的函数在压测结束时调用以导出剩余的span
+func Start(ctx context.Context, tc *conf.TraceConf) (func(), error) {
+	if tc == nil || tc.Endpoint == "" {
+		return func() {}, nil
+	}
+	exporter, err := newExporter(ctx, tc.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+		semconv.ServiceVersion(utils.Version),
+		semconv.ServiceInstanceID(utils.Instance()),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		// 是否采样在发压时决定，没有采样的请求不会创建span
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithBatcher(exporter, sdktrace.WithMaxQueueSize(maxQueueSize)),
+	)
+	sample := tc.Sample
+	if sample <= 0 {
+		sample = DefaultSample
+	}
+	m.Lock()
+	defer m.Unlock()
+	current.Store(&tracer{t: provider.Tracer(serviceName), sample: sample, provider: provider})
+	logger.Info("Trace %.2f%% of requests to %s", sample*100, tc.Endpoint)
+	return func() {
+		m.Lock()
+		defer m.Unlock()
+		if t := current.Load(); t != nil && t.provider == provider {
+			current.Store(nil)
+		}
+		if err := provider.Shutdown(context.Background()); err != nil {
+			logger.Error("Export traces err: %v", err)
+		}
+	}, nil
+}
+
+// newExporter http(s):// 开头时导出到 OTLP/HTTP collector，否则以 OTLP JSON 每行一个请求写入文件
+func newExporter(ctx context.Context, endpoint string) (sdktrace.SpanExporter, error) {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return newFileExporter(endpoint)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid trace endpoint %q: %v", conf.ErrInvalidConfig, endpoint, err)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/v1/traces"
+	}
+	return otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(u.String()))
+}
+
+// Enabled 当前是否开启了追踪
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// StartRequest 按采样比例为一次压测请求创建span，没有采样时返回nil
+func StartRequest(ctx context.Context, runID, worker string) (context.Context, trace.Span) {
+	t := current.Load()
+	if t == nil || rand.Float64() >= t.sample {
+		return ctx, nil
+	}
+	return t.t.Start(ctx, worker,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			AttrRunID.String(runID),
+			AttrWorker.String(worker),
+			AttrExecutor.String(utils.Instance()),
+			AttrOperation.String(worker),
+		))
+}
+
+// EndRequest 结束请求的span，err不为nil时记录错误
+func EndRequest(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// SetOperation 工作器在DoWorker中设置本次请求的操作名，例如 "GET /login"，没有采样时不做任何事
+func SetOperation(ctx context.Context, operation string) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetName(operation)
+	span.SetAttributes(AttrOperation.String(operation))
+}
+
+// Inject 把W3C trace context写入请求头，被压服务的span会关联到压测请求的span
+func Inject(ctx context.Context, header http.Header) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
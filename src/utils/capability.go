@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// WorkerInfo 执行器上安装的一个工作器
+type WorkerInfo struct {
+	Name          string `json:"name"`
+	DefaultConfig string `json:"default_config"`
+	// Schema 工作器配置的 JSON Schema，工作器没有提供时为空
+	Schema string `json:"schema,omitempty"`
+}
+
+// Capabilities 执行器注册时上报的能力和资源，控制器据此挑选合适的执行器
+type Capabilities struct {
+	Version string `json:"version"`
+	CPU     int    `json:"cpu"`
+	// Memory 系统总内存(字节)，无法获取时为0
+	Memory int64 `json:"memory"`
+	// MaxRate 推荐的最大发压速率(req/s)，0表示不限制
+	MaxRate int64             `json:"max_rate"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Workers []WorkerInfo      `json:"workers"`
+	// Admin 执行器管理接口(pprof等)的监听地址，未开启时为空
+	Admin string `json:"admin,omitempty"`
+}
+
+// NewCapabilities 根据本机资源生成能力描述
+func NewCapabilities(maxRate int64, labels map[string]string, workers []WorkerInfo) *Capabilities {
+	return &Capabilities{
+		Version: Version,
+		CPU:     runtime.NumCPU(),
+		Memory:  totalMemory(),
+		MaxRate: maxRate,
+		Labels:  labels,
+		Workers: workers,
+	}
+}
+
+// HasWorker 是否安装了指定的工作器
+func (c *Capabilities) HasWorker(name string) bool {
+	if c == nil {
+		return false
+	}
+	for _, w := range c.Workers {
+		if w.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseLabels 解析 zone=a,rack=r1 格式的标签
+func ParseLabels(s string) (map[string]string, error) {
+	labels := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		if kv = strings.TrimSpace(kv); kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return nil, fmt.Errorf("invalid label %q, want key=value", kv)
+		}
+		labels[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return labels, nil
+}
+
+// totalMemory 读取 /proc/meminfo 中的 MemTotal，非linux系统返回0
+func totalMemory() int64 {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0
+			}
+			return kb * 1024
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"perform-cli-framework-go/src/logger"
+	"runtime"
+	"time"
+)
+
+// maxBackoff 重新注册的最大退避时间
+const maxBackoff = 60 * time.Second
+
+// HeartbeatStatus 心跳中上报的执行器状态
+type HeartbeatStatus struct {
+	RunID    string
+	RunState string
+	Running  bool
+}
+
+// heartbeat 发送一次心跳，控制器不认识该执行器时返回 ErrNotRegistered
+func (ru *RegistrationUtils) heartbeat(st HeartbeatStatus) error {
+	data := map[string]interface{}{
+		"name":               ru.name,
+		"host":               fmt.Sprintf("%s:%d", ru.line.GrpcCfg.LocalIp, ru.line.GrpcCfg.Port),
+		"group_name":         ru.line.GrpcCfg.GroupName,
+		"cpu":                runtime.NumCPU(),
+		"version":            Version,
+		"run_id":             st.RunID,
+		"run_state":          st.RunState,
+		"running":            st.Running,
+		"heartbeat_interval": ru.line.GrpcCfg.HeartbeatInterval,
+	}
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	responseMap, err := ru.post("/v1/executor/heartbeat", string(js))
+	if err != nil {
+		return err
+	}
+	switch responseCode(responseMap) {
+	case 0:
+		return nil
+	case CodeNotRegistered:
+		return ErrNotRegistered
+	default:
+		return fmt.Errorf("unexpected response: %v", responseMap)
+	}
+}
+
+// Heartbeat 按 HeartbeatInterval 向控制器发送心跳，阻塞直到ctx取消
+// 心跳失败时切换控制器并以指数退避重新注册；压测过程中与控制器失联超过 SafetyStop 秒时调用一次onLost
+func (ru *RegistrationUtils) Heartbeat(ctx context.Context, status func() HeartbeatStatus, onLost func()) {
+	if ru.name == "" {
+		return
+	}
+	interval := time.Duration(ru.line.GrpcCfg.HeartbeatInterval) * time.Second
+	safety := ru.line.GrpcCfg.SafetyStop * 1000 * 1000
+	lastSeen := GetTimeUs()
+	stopped := false
+	backoff := time.Second
+	wait := interval
+	for {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-ru.done:
+			// 已经注销，不再发送心跳，避免下一次心跳又重新注册
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		st := status()
+		err := ru.heartbeat(st)
+		if err == ErrNotRegistered {
+			logger.Warning("Executor not registered on %s, register again", ru.Endpoint())
+			err = ru.register()
+		}
+		if errors.Is(err, errUnregistered) {
+			return
+		}
+		if err == nil {
+			lastSeen = GetTimeUs()
+			stopped = false
+			backoff = time.Second
+			wait = interval
+			continue
+		}
+		logger.Warning("Heartbeat to %s with err: %v", ru.Endpoint(), err)
+		ru.failover()
+		// 切换后立即在新的控制器上重新注册
+		err = ru.register()
+		if errors.Is(err, errUnregistered) {
+			return
+		}
+		if err == nil {
+			lastSeen = GetTimeUs()
+			stopped = false
+			backoff = time.Second
+			wait = interval
+			continue
+		}
+		logger.Warning("Register to %s with err: %v, retry in %v", ru.Endpoint(), err, backoff)
+		lost := GetTimeUs() - lastSeen
+		if st.Running && safety > 0 && lost >= safety && !stopped {
+			stopped = true
+			onLost()
+		}
+		wait = backoff
+		if st.Running && safety > 0 {
+			// 压测中保持检测频率，保证及时触发安全停止
+			wait = min(wait, interval)
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
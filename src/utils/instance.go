@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+var instance atomic.Value
+
+// SetInstance 设置执行器名称，用于链路追踪和直方图日志中区分执行器
+func SetInstance(name string) {
+	instance.Store(name)
+}
+
+// Instance 执行器名称，没有设置时为主机名
+func Instance() string {
+	if v, ok := instance.Load().(string); ok && v != "" {
+		return v
+	}
+	host, _ := os.Hostname()
+	return host
+}
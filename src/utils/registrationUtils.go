@@ -3,19 +3,41 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"perform-cli-framework-go/src/logger"
+	"strings"
+	"sync"
 
 	"net/http"
 	"perform-cli-framework-go/src/conf"
 	"time"
 )
 
+// ErrNotRegistered 控制器不认识该执行器(例如控制器重启过)，需要重新注册
+var ErrNotRegistered = errors.New("executor not registered")
+
+// errUnregistered 执行器已经注销，不再注册
+var errUnregistered = errors.New("executor unregistered")
+
+// CodeNotRegistered 控制器心跳接口返回的未注册错误码
+const CodeNotRegistered = 404
+
 type RegistrationUtils struct {
-	client   *http.Client
-	name     string
-	endpoint string
+	client *http.Client
+	name   string
+	line   conf.BenchConfig
+	caps   *Capabilities
+	m      sync.Mutex
+	// endpoints 控制器地址列表，当前使用的是endpoints[current]，失败时切换到下一个
+	endpoints []string
+	current   int
+	// regM 串行化注册和注销，注销之后不再注册
+	regM         sync.Mutex
+	unregistered bool
+	// done 注销时关闭，心跳随之停止
+	done chan struct{}
 }
 
 func NewRegistrationUtils() *RegistrationUtils {
@@ -23,27 +45,94 @@ func NewRegistrationUtils() *RegistrationUtils {
 		client: &http.Client{
 			Timeout: 10 * time.Second, // 设置超时时间
 		},
+		done: make(chan struct{}),
 	}
 }
 
+// Endpoint 当前使用的控制器地址
+func (ru *RegistrationUtils) Endpoint() string {
+	ru.m.Lock()
+	defer ru.m.Unlock()
+	if len(ru.endpoints) == 0 {
+		return ""
+	}
+	return ru.endpoints[ru.current]
+}
+
+// failover 切换到下一个控制器地址
+func (ru *RegistrationUtils) failover() {
+	ru.m.Lock()
+	defer ru.m.Unlock()
+	if len(ru.endpoints) > 1 {
+		ru.current = (ru.current + 1) % len(ru.endpoints)
+		logger.Warning("Switch to remote controller: %s", ru.endpoints[ru.current])
+	}
+}
+
+// SetCapabilities 设置注册时上报的执行器能力
+func (ru *RegistrationUtils) SetCapabilities(caps *Capabilities) {
+	ru.caps = caps
+}
+
+// Register 注册到控制器，-R 可以用逗号分隔多个控制器地址，依次尝试直到成功
 func (ru *RegistrationUtils) Register(line conf.BenchConfig) error {
 	if line.GrpcCfg.RegistrationCtEndpoint == "" {
 		return nil
 	}
-	logger.Info("Register to remote controller: %s", line.GrpcCfg.RegistrationCtEndpoint)
-	jsonString, err := ru.getString(line)
+	ru.m.Lock()
+	ru.line = line
+	ru.endpoints = ru.endpoints[:0]
+	for _, e := range strings.Split(line.GrpcCfg.RegistrationCtEndpoint, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			ru.endpoints = append(ru.endpoints, e)
+		}
+	}
+	ru.current = 0
+	ru.m.Unlock()
+	var err error
+	for range ru.endpoints {
+		if err = ru.register(); err == nil {
+			return nil
+		}
+		logger.Warning("Register to %s with err: %v", ru.Endpoint(), err)
+		ru.failover()
+	}
+	return err
+}
+
+func (ru *RegistrationUtils) register() error {
+	ru.regM.Lock()
+	defer ru.regM.Unlock()
+	if ru.unregistered {
+		return errUnregistered
+	}
+	logger.Info("Register to remote controller: %s", ru.Endpoint())
+	jsonString, err := ru.getString(ru.line)
 	if err != nil {
 		return err
 	}
-	ru.endpoint = line.GrpcCfg.RegistrationCtEndpoint
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/executor/add", ru.endpoint), bytes.NewBuffer([]byte(jsonString)))
+	responseMap, err := ru.post("/v1/executor/add", jsonString)
 	if err != nil {
 		return err
 	}
+	logger.Info("R body: %v", responseMap)
+	// 检查 code 是否为 0
+	if responseCode(responseMap) == 0 {
+		return nil
+	}
+	return fmt.Errorf("unexpected response: %v", responseMap)
+}
+
+// post 向当前控制器发送json请求并解析返回
+func (ru *RegistrationUtils) post(path string, body string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", ru.Endpoint(), path), bytes.NewBuffer([]byte(body)))
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", "application/json")
 	resp, err := ru.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -53,16 +142,19 @@ func (ru *RegistrationUtils) Register(line conf.BenchConfig) error {
 	}(resp.Body)
 	var responseMap map[string]interface{}
 	if err := json.NewDecoder(resp.Body).Decode(&responseMap); err != nil {
-		return err
+		return nil, err
 	}
-	logger.Info("R body: %v", responseMap)
-	// 检查 code 是否为 0
+	return responseMap, nil
+}
+
+// responseCode 返回控制器返回中的code，没有code时返回-1
+func responseCode(responseMap map[string]interface{}) int {
 	if code, ok := responseMap["code"]; ok {
-		if v, ok := code.(float64); ok && int(v) == 0 {
-			return nil
+		if v, ok := code.(float64); ok {
+			return int(v)
 		}
 	}
-	return fmt.Errorf("unexpected response: %v", responseMap)
+	return -1
 }
 
 func (ru *RegistrationUtils) getString(line conf.BenchConfig) (string, error) {
@@ -72,11 +164,13 @@ func (ru *RegistrationUtils) getString(line conf.BenchConfig) (string, error) {
 		return "", err
 	}
 	data := map[string]interface{}{
-		"name":            ru.name,
-		"host":            fmt.Sprintf("%s:%d", line.GrpcCfg.LocalIp, line.GrpcCfg.Port),
-		"group_name":      line.GrpcCfg.GroupName,
-		"executor_config": string(js),
-		"description":     "Executor auto add",
+		"name":               ru.name,
+		"host":               fmt.Sprintf("%s:%d", line.GrpcCfg.LocalIp, line.GrpcCfg.Port),
+		"group_name":         line.GrpcCfg.GroupName,
+		"executor_config":    string(js),
+		"description":        "Executor auto add",
+		"heartbeat_interval": line.GrpcCfg.HeartbeatInterval,
+		"capabilities":       ru.caps,
 	}
 
 	jsonData, err := json.Marshal(data)
@@ -86,31 +180,23 @@ func (ru *RegistrationUtils) getString(line conf.BenchConfig) (string, error) {
 	return string(jsonData), nil
 }
 
+// Unregister 从控制器注销并停止心跳，等待进行中的注册完成后再注销，避免注销后又被注册
 func (ru *RegistrationUtils) Unregister() error {
+	ru.regM.Lock()
+	defer ru.regM.Unlock()
+	if !ru.unregistered {
+		ru.unregistered = true
+		close(ru.done)
+	}
 	if ru.name == "" {
 		return nil
 	}
 	logger.Info("unRegister from remote controller")
 	jsonString := fmt.Sprintf("{\"name\": \"%s\"}", ru.name)
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/v1/executor/del", ru.endpoint), bytes.NewBuffer([]byte(jsonString)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := ru.client.Do(req)
+	responseMap, err := ru.post("/v1/executor/del", jsonString)
 	if err != nil {
 		return err
 	}
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-
-		}
-	}(resp.Body)
-	var responseMap map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&responseMap); err != nil {
-		return err
-	}
 
 	logger.Info("UR body: %v", responseMap)
 
@@ -0,0 +1,4 @@
+package utils
+
+// Version 程序版本，构建时通过 -ldflags "-X perform-cli-framework-go/src/utils.Version=x.y.z" 设置
+var Version = "dev"
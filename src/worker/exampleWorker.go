@@ -18,6 +18,10 @@ func (w *ExampleWorker) DefaultConfig() string {
 	return "{}"
 }
 
+func (w *ExampleWorker) ConfigSchema() string {
+	return `{"type": "object", "properties": {}}`
+}
+
 func (w *ExampleWorker) Clone() Worker {
 	// 深浅取决于需求，思路是先实例化一个worker，在SetupGlobal后使用该实例进行克隆
 	return &ExampleWorker{}
@@ -44,6 +48,10 @@ func (w *ExampleWorker) DoWorker(data *conf.GoData) error {
 	data.StaterI.RecordBytes(10, true)
 	// 接受的字节数
 	data.StaterI.RecordBytes(20, false)
+	// 自定义指标：计数、仪表和直方图
+	data.Count("example.items", 1)
+	data.Gauge("example.goroutine", float64(data.Goroutine))
+	data.Observe("example.batch_size", 1+data.SendTotal%10)
 	return nil
 }
 
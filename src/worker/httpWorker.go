@@ -0,0 +1,139 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/tracing"
+	"strings"
+	"time"
+)
+
+// HttpConfig HttpWorker 的配置，从 WorkerConfig 解析
+type HttpConfig struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+	// Expect 期望的状态码，0表示小于400都算成功
+	Expect int `json:"expect"`
+}
+
+// StatusError 响应的状态码不符合期望，请求日志中按状态码分类
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.Code)
+}
+
+func (e *StatusError) ErrorClass() string {
+	return fmt.Sprintf("http_%d", e.Code)
+}
+
+// HttpWorker 内置的http工作器，每次请求一个url，开启链路追踪时在请求头中传递W3C trace context
+// 通过 PhaseTrace 记录 dns、connect、tls、ttfb、transfer 各阶段的耗时
+type HttpWorker struct {
+	cfg       HttpConfig
+	client    *http.Client
+	operation string
+}
+
+func (w *HttpWorker) NewInstance() Worker {
+	return &HttpWorker{}
+}
+
+func (w *HttpWorker) DefaultConfig() string {
+	return `{"url": "http://127.0.0.1:8080/", "method": "GET", "headers": {}, "body": "", "expect": 0}`
+}
+
+func (w *HttpWorker) ConfigSchema() string {
+	return `{"type": "object", "required": ["url"], "properties": {` +
+		`"url": {"type": "string"}, ` +
+		`"method": {"type": "string", "default": "GET"}, ` +
+		`"headers": {"type": "object", "additionalProperties": {"type": "string"}}, ` +
+		`"body": {"type": "string"}, ` +
+		`"expect": {"type": "integer", "description": "expected status code, 0 for any status below 400"}}}`
+}
+
+func (w *HttpWorker) Clone() Worker {
+	// 共享全局前置中解析的配置和连接池
+	return &HttpWorker{cfg: w.cfg, client: w.client, operation: w.operation}
+}
+
+func (w *HttpWorker) SetupGlobal(c context.Context, config conf.BenchConfig) error {
+	if err := json.Unmarshal([]byte(config.WorkerConfig), &w.cfg); err != nil {
+		return fmt.Errorf("parse http worker config: %w", err)
+	}
+	if w.cfg.Method == "" {
+		w.cfg.Method = http.MethodGet
+	}
+	u, err := url.Parse(w.cfg.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid http worker url %q", w.cfg.URL)
+	}
+	w.operation = fmt.Sprintf("%s %s", w.cfg.Method, u.Path)
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = int(config.Workers)
+	w.client = &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(config.Timeout) * time.Second,
+	}
+	logger.Info("Http worker: %s %s", w.cfg.Method, w.cfg.URL)
+	return nil
+}
+
+func (w *HttpWorker) PostGlobal(c context.Context, config conf.BenchConfig) error {
+	if w.client != nil {
+		w.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+func (w *HttpWorker) Setup(data *conf.GoData) error {
+	return nil
+}
+
+func (w *HttpWorker) DoWorker(data *conf.GoData) error {
+	var body io.Reader
+	if w.cfg.Body != "" {
+		body = strings.NewReader(w.cfg.Body)
+	}
+	ctx, phases := NewPhaseTrace(data.Ctx, data)
+	req, err := http.NewRequestWithContext(ctx, w.cfg.Method, w.cfg.URL, body)
+	if err != nil {
+		return err
+	}
+	for k, v := range w.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	data.Operation = w.operation
+	tracing.Inject(data.Ctx, req.Header)
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if err == nil {
+		phases.Done()
+	}
+	data.StaterI.RecordBytes(int64(len(w.cfg.Body)), true)
+	data.StaterI.RecordBytes(n, false)
+	if err != nil {
+		return err
+	}
+	if (w.cfg.Expect > 0 && resp.StatusCode != w.cfg.Expect) || (w.cfg.Expect == 0 && resp.StatusCode >= 400) {
+		return &StatusError{Code: resp.StatusCode}
+	}
+	return nil
+}
+
+func (w *HttpWorker) Post(data *conf.GoData) {
+}
@@ -4,4 +4,5 @@ var workers = make(map[string]Worker)
 
 func init() {
 	workers["ExampleWorker"] = &ExampleWorker{}
+	workers["HttpWorker"] = &HttpWorker{}
 }
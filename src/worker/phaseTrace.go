@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"perform-cli-framework-go/src/conf"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/utils"
+	"sync"
+)
+
+// PhaseTrace 通过 httptrace 记录一次http请求各阶段的耗时，自定义的http工作器也可以使用
+// 复用连接的请求没有 dns、connect、tls 阶段，ttfb 从请求发送完成开始计算
+type PhaseTrace struct {
+	stater    stat.Stater
+	mu        sync.Mutex
+	dnsStart  int64
+	connStart int64
+	tlsStart  int64
+	wrote     int64
+	firstByte int64
+}
+
+// NewPhaseTrace 返回带有 httptrace 的 context，请求使用该 context 发出，读完响应后调用 Done
+func NewPhaseTrace(ctx context.Context, data *conf.GoData) (context.Context, *PhaseTrace) {
+	// 拨号在其他协程中进行，请求结束后仍可能回调，记录到底层的统计中
+	// 写请求日志时 StaterI 是每次请求都会重置的 byteCounter，不能在回调中使用
+	stater := data.StaterI
+	if c, ok := stater.(*byteCounter); ok {
+		stater = c.Stater
+	}
+	t := &PhaseTrace{stater: stater}
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mark(&t.dnsStart)
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			if info.Err == nil {
+				t.since(stat.PhaseDNS, &t.dnsStart)
+			}
+		},
+		ConnectStart: func(_, _ string) {
+			t.mark(&t.connStart)
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.since(stat.PhaseConnect, &t.connStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mark(&t.tlsStart)
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.since(stat.PhaseTLS, &t.tlsStart)
+			}
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err == nil {
+				t.mark(&t.wrote)
+			}
+		},
+		GotFirstResponseByte: func() {
+			now := utils.GetTimeUs()
+			t.mu.Lock()
+			t.firstByte = now
+			wrote := t.wrote
+			t.mu.Unlock()
+			if wrote > 0 {
+				t.record(stat.PhaseTTFB, now-wrote)
+			}
+		},
+	}
+	return httptrace.WithClientTrace(ctx, trace), t
+}
+
+// mark 记录阶段的开始时间，并发拨号时以最早的一次为准
+func (t *PhaseTrace) mark(p *int64) {
+	now := utils.GetTimeUs()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if *p == 0 {
+		*p = now
+	}
+}
+
+// since 记录从阶段开始到现在的耗时，只记录一次
+func (t *PhaseTrace) since(phase string, p *int64) {
+	now := utils.GetTimeUs()
+	t.mu.Lock()
+	begin := *p
+	*p = -1
+	t.mu.Unlock()
+	if begin > 0 {
+		t.record(phase, now-begin)
+	}
+}
+
+func (t *PhaseTrace) record(phase string, us int64) {
+	t.stater.RecordPhase(phase, us)
+}
+
+// Done 读完响应后调用，记录 transfer 阶段
+func (t *PhaseTrace) Done() {
+	now := utils.GetTimeUs()
+	t.mu.Lock()
+	first := t.firstByte
+	t.mu.Unlock()
+	if first > 0 {
+		t.record(stat.PhaseTransfer, now-first)
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"os"
 	"perform-cli-framework-go/src/conf"
 	"perform-cli-framework-go/src/logger"
+	"perform-cli-framework-go/src/reqlog"
+	"perform-cli-framework-go/src/stat"
+	"perform-cli-framework-go/src/tracing"
 	"perform-cli-framework-go/src/utils"
+	"sort"
 	"sync"
 )
 
@@ -22,6 +25,11 @@ type Worker interface {
 	Clone() Worker
 }
 
+// SchemaProvider 工作器可选实现，返回工作器配置的 JSON Schema，注册时上报给控制器
+type SchemaProvider interface {
+	ConfigSchema() string
+}
+
 var m = sync.Mutex{}
 var b = false
 var f = false
@@ -33,8 +41,13 @@ func ResetStatus() {
 
 var ExitError = errors.New("exit worker")
 
+var ErrUnknownWorker = errors.New("unknown worker")
+
 type Proxy struct {
 	workerHandler Worker
+	name          string
+	// counter 每个发压协程一个Proxy，写请求日志时复用
+	counter byteCounter
 }
 
 func (w *Proxy) SetupGlobal(c context.Context, config conf.BenchConfig) error {
@@ -76,29 +89,88 @@ func (w *Proxy) Setup(data *conf.GoData) error {
 }
 
 func (w *Proxy) DoWorker(data *conf.GoData) error {
+	// 采样到的请求创建span，工作器通过data.Ctx传递trace context
+	parent := data.Ctx
+	ctx, span := tracing.StartRequest(parent, data.RunID, w.name)
+	data.Ctx = ctx
+	data.Operation = ""
+	// 需要写请求日志时统计本次请求的收发字节数
+	logged := reqlog.Sampled()
+	stater := data.StaterI
+	if logged {
+		w.counter = byteCounter{Stater: stater}
+		data.StaterI = &w.counter
+	}
+	begin := utils.GetTimeUs()
 	defer func() {
 		data.SendTotal++
+		data.Ctx = parent
+		data.StaterI = stater
 		if p := recover(); p != nil {
+			tracing.EndRequest(span, fmt.Errorf("panic: %v", p))
 			data.StaterI.RecordErr(fmt.Sprintf("do work err %v", p))
+			if logged {
+				w.logRequest(data, begin, utils.GetTimeUs(), reqlog.ClassPanic)
+			}
 		}
 	}()
-	begin := utils.GetTimeUs()
 	err := w.workerHandler.DoWorker(data)
+	after := utils.GetTimeUs()
+	if data.Operation != "" {
+		tracing.SetOperation(ctx, data.Operation)
+	}
+	if err == ExitError {
+		tracing.EndRequest(span, nil)
+		return ExitError
+	}
+	tracing.EndRequest(span, err)
+	if logged {
+		w.logRequest(data, begin, after, reqlog.Classify(err))
+	}
 	if err != nil {
-		if err == ExitError {
-			return ExitError
-		}
 		if data.Cfg.PError {
 			logger.Error("Do worker with err: %v", err)
 		}
 		data.StaterI.RecordErr(fmt.Sprintf("do work err %v", err))
 		return nil
 	}
-	after := utils.GetTimeUs()
 	data.StaterI.AddLatency(after - begin)
 	return nil
 }
 
+// logRequest 把本次请求写入请求日志，没有设置操作名时使用工作器名称
+func (w *Proxy) logRequest(data *conf.GoData, begin, after int64, class string) {
+	op := data.Operation
+	if op == "" {
+		op = w.name
+	}
+	reqlog.Log(reqlog.Record{
+		Time:      begin,
+		Goroutine: data.Goroutine,
+		Operation: op,
+		Latency:   after - begin,
+		SendBytes: w.counter.send,
+		RecvBytes: w.counter.recv,
+		Error:     class,
+	})
+}
+
+// byteCounter 在转发给统计的同时累计单次请求的收发字节数
+type byteCounter struct {
+	stat.Stater
+	send int64
+	recv int64
+}
+
+func (c *byteCounter) RecordBytes(value int64, isSend bool) {
+	if isSend {
+		c.send += value
+	} else {
+		c.recv += value
+	}
+	c.Stater.RecordBytes(value, isSend)
+}
+
 func (w *Proxy) Post(data *conf.GoData) {
 	w.workerHandler.Post(data)
 }
@@ -110,23 +182,38 @@ func (w *Proxy) NewInstance() Worker {
 func (w *Proxy) Clone() Worker {
 	return &Proxy{
 		workerHandler: w.workerHandler.Clone(),
+		name:          w.name,
 	}
 }
 
-// NewWorker 根据需要返回实现得worker
-func NewWorker(name string) *Proxy {
-	var w Worker
-	if value, ok := workers[name]; ok {
-		w = value
-	} else {
-		fmt.Printf("can not found worker %s\n", name)
-		os.Exit(-1)
+// NewWorker 根据需要返回实现得worker，找不到时返回ErrUnknownWorker
+func NewWorker(name string) (*Proxy, error) {
+	w, ok := workers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWorker, name)
 	}
 	return &Proxy{
+		name:          name,
 		workerHandler: w.NewInstance(),
-	}
+	}, nil
 }
 
 func GetAllWorkers() map[string]Worker {
 	return workers
 }
+
+// WorkerInfos 返回所有工作器的默认配置和配置Schema，按名称排序
+func WorkerInfos() []utils.WorkerInfo {
+	res := make([]utils.WorkerInfo, 0, len(workers))
+	for name, w := range workers {
+		info := utils.WorkerInfo{Name: name, DefaultConfig: w.DefaultConfig()}
+		if sp, ok := w.(SchemaProvider); ok {
+			info.Schema = sp.ConfigSchema()
+		}
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}